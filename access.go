@@ -0,0 +1,101 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// clfTimeFormat is the timestamp layout used by the NCSA Common Log Format
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogFormatter formats HTTP access log entries in the NCSA Common Log Format:
+//
+//	127.0.0.1 - user [02/Jan/2006:15:04:05 -0700] "GET /path HTTP/1.1" 200 1234
+//
+// The line is built from the entry's "remote", "user", "method", "path", "proto",
+// "status", and "bytes" fields. Missing fields are written as "-", and "proto"
+// defaults to HTTP/1.1. Quotes, backslashes and control characters in the fields are
+// escaped as Apache does, so a request cannot forge log lines.
+type AccessLogFormatter struct{}
+
+func (f *AccessLogFormatter) Format(level LogLevel, message string) string {
+	return f.FormatEntry(newEntry(level, message, false))
+}
+
+// FormatEntry formats an entry as a Common Log Format line
+func (f *AccessLogFormatter) FormatEntry(e Entry) string {
+	request := "-"
+	method, hasMethod := e.Field("method")
+	path, hasPath := e.Field("path")
+	if hasMethod || hasPath {
+		proto := "HTTP/1.1"
+		if value, ok := e.Field("proto"); ok {
+			proto = clfEscape(fmt.Sprint(value))
+		}
+		request = fmt.Sprintf("%s %s %s", clfValue(method, hasMethod), clfValue(path, hasPath), proto)
+	}
+
+	return fmt.Sprintf("%s - %s [%s] \"%s\" %s %s\n",
+		clfField(e, "remote"),
+		clfField(e, "user"),
+		e.Time.Format(clfTimeFormat),
+		request,
+		clfField(e, "status"),
+		clfField(e, "bytes"),
+	)
+}
+
+// clfField returns the entry's field as a string, or "-" if it is missing or empty
+func clfField(e Entry, key string) string {
+	value, ok := e.Field(key)
+	return clfValue(value, ok)
+}
+
+// clfValue returns value as a string, or "-" if it is missing or empty
+func clfValue(value interface{}, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	s := fmt.Sprint(value)
+	if s == "" {
+		return "-"
+	}
+	return clfEscape(s)
+}
+
+// clfEscape escapes quotes, backslashes and control characters the way Apache's access
+// log does: \" and \\, \n and the other C escapes, and \xHH for the remaining bytes
+func clfEscape(s string) string {
+	if !strings.ContainsFunc(s, func(r rune) bool { return r < 0x20 || r == 0x7f || r == '"' || r == '\\' }) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\v':
+			b.WriteString(`\v`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (f *AccessLogFormatter) reportsCaller() bool {
+	return false
+}
@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// clfTimestamp matches the bracketed Common Log Format timestamp
+var clfTimestamp = regexp.MustCompile(`\[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\]`)
+
+// TestAccessLogFormatter verifies that a complete set of fields produces a Common Log Format line
+func TestAccessLogFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.AccessLogFormatter{})
+
+	logger.WithFields(log.Fields{
+		"remote": "127.0.0.1",
+		"user":   "frank",
+		"method": "GET",
+		"path":   "/apache_pb.gif",
+		"status": 200,
+		"bytes":  2326,
+	}).Info("request")
+
+	expected := `127.0.0.1 - frank [TIME] "GET /apache_pb.gif HTTP/1.1" 200 2326` + "\n"
+	if !clfTimestamp.Match(buf.Bytes()) {
+		t.Fatalf("Expected a Common Log Format timestamp, got %v", buf.String())
+	}
+	if got := clfTimestamp.ReplaceAllString(buf.String(), "[TIME]"); got != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, got)
+	}
+}
+
+// TestAccessLogFormatter_MissingFields verifies that missing fields are written as "-"
+func TestAccessLogFormatter_MissingFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.AccessLogFormatter{})
+
+	logger.WithField("method", "POST").Info("request")
+
+	expected := `- - - [TIME] "POST - HTTP/1.1" - -` + "\n"
+	if got := clfTimestamp.ReplaceAllString(buf.String(), "[TIME]"); got != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, got)
+	}
+
+	buf.Reset()
+	logger.Info("request")
+
+	expected = `- - - [TIME] "-" - -` + "\n"
+	if got := clfTimestamp.ReplaceAllString(buf.String(), "[TIME]"); got != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, got)
+	}
+}
+
+// TestAccessLogFormatter_Escaping verifies that quotes, backslashes and control characters cannot forge a second line
+func TestAccessLogFormatter_Escaping(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.AccessLogFormatter{})
+
+	logger.WithFields(log.Fields{
+		"remote": "127.0.0.1",
+		"user":   "a\\b",
+		"method": "GET",
+		"path":   "/a\n6.6.6.6 - admin [01/Jan/2024:00:00:00 +0000] \"GET /secret HTTP/1.1\" 200 1\x01",
+		"status": 200,
+		"bytes":  1,
+	}).Info("request")
+
+	expected := `127.0.0.1 - a\\b [TIME] "GET /a\n6.6.6.6 - admin [TIME] \"GET /secret HTTP/1.1\" 200 1\x01 HTTP/1.1" 200 1` + "\n"
+	if got := clfTimestamp.ReplaceAllString(buf.String(), "[TIME]"); got != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, got)
+	}
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrQueueFull is returned by AsyncWriter.Write when the queue has no room and the entry is dropped
+var ErrQueueFull = errors.New("log queue full")
+
+// ErrWriterClosed is returned by AsyncWriter.Write after Close
+var ErrWriterClosed = errors.New("log writer closed")
+
+// AsyncWriter hands entries to a background goroutine that writes them to another writer,
+// so logging does not wait on a slow output. The queue is bounded: when it is full, Write
+// drops the entry and returns ErrQueueFull instead of blocking.
+type AsyncWriter struct {
+	output io.Writer
+	queue  chan asyncItem
+	done   chan struct{}
+
+	mu     sync.RWMutex // Guards closed against concurrent Write and Close
+	closed bool
+}
+
+// asyncItem is either an entry to write or, when flushed is set, a flush request
+type asyncItem struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// NewAsyncWriter creates an AsyncWriter that queues up to queueSize entries for output
+func NewAsyncWriter(output io.Writer, queueSize int) *AsyncWriter {
+	w := &AsyncWriter{
+		output: output,
+		queue:  make(chan asyncItem, queueSize),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// run writes queued entries until the queue is closed
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for item := range w.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		w.output.Write(item.data)
+	}
+}
+
+// Write queues a copy of p, returning ErrQueueFull without blocking if the queue is full
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	select {
+	case w.queue <- asyncItem{data: data}:
+		return len(p), nil
+	default:
+		return 0, ErrQueueFull
+	}
+}
+
+// Flush waits until every entry queued before the call has been written
+func (w *AsyncWriter) Flush() error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	w.queue <- asyncItem{flushed: flushed}
+	w.mu.RUnlock()
+	<-flushed
+	return nil
+}
+
+func (w *AsyncWriter) writesInBackground() {}
+
+// Close writes the remaining queued entries and stops the background goroutine.
+// The underlying writer is not closed.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+	return nil
+}
@@ -0,0 +1,108 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// gatedWriter blocks every Write until the gate is opened, signalling when a write starts
+type gatedWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// TestAsyncWriter_QueueFull verifies that writes are dropped with ErrQueueFull once the queue is full
+func TestAsyncWriter_QueueFull(t *testing.T) {
+	output := newGatedWriter()
+	w := log.NewAsyncWriter(output, 1)
+
+	io.WriteString(w, "first\n")
+	<-output.started // The first entry has left the queue and is blocked in output
+	if _, err := io.WriteString(w, "second\n"); err != nil {
+		t.Fatalf("Expected the second entry to be queued, got %v", err)
+	}
+	if _, err := io.WriteString(w, "third\n"); !errors.Is(err, log.ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	close(output.gate)
+	w.Close()
+	if output.String() != "first\nsecond\n" {
+		t.Errorf("Expected the queued entries to be written, got %q", output.String())
+	}
+	if _, err := io.WriteString(w, "late\n"); !errors.Is(err, log.ErrWriterClosed) {
+		t.Errorf("Expected ErrWriterClosed after Close, got %v", err)
+	}
+}
+
+// TestAsyncWriter_Flush verifies that Flush waits for queued entries to be written
+func TestAsyncWriter_Flush(t *testing.T) {
+	var buf bytes.Buffer
+	w := log.NewAsyncWriter(&buf, 16)
+	defer w.Close()
+	logger := log.NewLogger(w, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.Info("Queued message")
+	logger.Flush()
+
+	if !containsLogMessage(buf.String(), "INFO", "Queued message") {
+		t.Errorf("Expected the message to be written after Flush, got %q", buf.String())
+	}
+}
+
+// TestAsyncWriter_LogTimeTimestamp verifies that an entry drained late keeps the time it was logged at
+func TestAsyncWriter_LogTimeTimestamp(t *testing.T) {
+	var clockMu sync.Mutex
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	output := newGatedWriter()
+	w := log.NewAsyncWriter(output, 16)
+	logger := log.NewLogger(w, log.INFO, &log.JSONFormatter{DisableCaller: true})
+	logger.SetClock(clockFunc(func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return clock
+	}))
+
+	logger.Info("Delayed message")
+	<-output.started
+	clockMu.Lock()
+	clock = clock.Add(time.Minute)
+	clockMu.Unlock()
+	close(output.gate)
+	w.Close()
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(output.String()), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["timestamp"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("Expected the log-call timestamp 2024-05-01T12:00:00Z, got %v", entry["timestamp"])
+	}
+}
@@ -0,0 +1,370 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Default batching settings
+const (
+	DefaultBatchSize    = 100
+	DefaultBatchTimeout = time.Second
+	DefaultMaxRetries   = 3
+	DefaultRetryBackoff = 100 * time.Millisecond
+	DefaultSendTimeout  = 5 * time.Second
+)
+
+// maxQueuedBatches is how many full batches may wait for the sender before new ones are dropped
+const maxQueuedBatches = 16
+
+// BatchOptions configures how a BatchWriter or Batcher groups entries before sending them
+type BatchOptions struct {
+	BatchSize    int                  // Send once this many entries are queued, DefaultBatchSize if zero
+	BatchTimeout time.Duration        // Send queued entries after this delay even if the batch is not full, DefaultBatchTimeout if zero
+	JSONArray    bool                 // Send batches as a JSON array instead of newline-delimited entries
+	MaxRetries   int                  // Retries of a batch that failed to send, DefaultMaxRetries if zero, none if negative
+	RetryBackoff time.Duration        // Delay before the first retry, doubled for each further one, DefaultRetryBackoff if zero
+	Retryable    func(err error) bool // Reports whether a failed send is retried; every error is retried if nil
+	Timeout      time.Duration        // Deadline of each request made by NewHTTPWriter or NewNetworkWriter, DefaultSendTimeout if zero
+	ErrorHandler func(err error)      // Called with the error of a batch that failed in the background; if nil, the error is returned by the next Flush
+}
+
+// Batcher groups entries of any type and passes each group to a send function. A batch
+// is sent as soon as it holds BatchSize entries, or BatchTimeout after its first entry
+// was added, whichever comes first. Flush and Close send a partial batch.
+//
+// Batches are sent in order by a background goroutine, so a slow or unreachable
+// endpoint never blocks the logging call. A batch that still fails after MaxRetries
+// retries is dropped, and the error is passed to the ErrorHandler option, or returned
+// by the next Flush if there is none. Adding entries is not affected by earlier
+// failures. Close stops the goroutine.
+//
+// BatchWriter batches formatted text with a Batcher; outputs that send entries in
+// another form, such as the logproto sink, can use one directly.
+type Batcher[T any] struct {
+	send    func(batch []T) error
+	options BatchOptions
+	queue   chan batchRequest[T]
+	stopped chan struct{}
+
+	closeMu sync.RWMutex // Held for reading while Flush hands a batch to the sender, so Close cannot close queue under it
+	mu      sync.Mutex   // Guards pending, timer, closed and err
+	pending []T
+	timer   *time.Timer
+	closed  bool
+	err     error // Failure of a background send without an ErrorHandler, returned by the next Flush
+}
+
+// batchRequest is a batch handed to the sender goroutine, with a channel to report
+// the result on when Flush is waiting for it
+type batchRequest[T any] struct {
+	batch []T
+	done  chan error
+}
+
+// NewBatcher creates a Batcher that passes each batch to send
+func NewBatcher[T any](send func(batch []T) error, options BatchOptions) *Batcher[T] {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.BatchTimeout <= 0 {
+		options.BatchTimeout = DefaultBatchTimeout
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = DefaultMaxRetries
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultRetryBackoff
+	}
+	b := &Batcher[T]{
+		send:    send,
+		options: options,
+		queue:   make(chan batchRequest[T], maxQueuedBatches),
+		stopped: make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Add queues entry, handing the batch to the sender if it is full. It returns
+// os.ErrClosed after Close.
+func (b *Batcher[T]) Add(entry T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return os.ErrClosed
+	}
+	b.pending = append(b.pending, entry)
+	var err error
+	if len(b.pending) >= b.options.BatchSize {
+		err = b.dispatchLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.options.BatchTimeout, func() {
+			b.mu.Lock()
+			err := b.dispatchLocked()
+			b.mu.Unlock()
+			b.report(err)
+		})
+	}
+	b.mu.Unlock()
+	b.report(err)
+	return nil
+}
+
+// dispatchLocked hands the pending entries to the sender without waiting for them to
+// be sent, dropping them if the sender is too far behind. It returns the error to
+// report for a dropped batch. b.mu must be held.
+func (b *Batcher[T]) dispatchLocked() error {
+	batch := b.takePendingLocked()
+	if len(batch) == 0 || b.closed {
+		return nil
+	}
+	select {
+	case b.queue <- batchRequest[T]{batch: batch}:
+		return nil
+	default:
+		return fmt.Errorf("dropped a log batch of %d entries: the sender is falling behind", len(batch))
+	}
+}
+
+// report passes the error of a batch that failed in the background to the ErrorHandler
+// option, or keeps it for the next Flush. It must be called without b.mu held, as the
+// handler may log to the same output.
+func (b *Batcher[T]) report(err error) {
+	if err == nil {
+		return
+	}
+	if b.options.ErrorHandler != nil {
+		b.options.ErrorHandler(err)
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// takePendingLocked returns the pending entries and resets the batch. b.mu must be held.
+func (b *Batcher[T]) takePendingLocked() []T {
+	batch := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return batch
+}
+
+// run sends the queued batches in order until Close closes the queue
+func (b *Batcher[T]) run() {
+	defer close(b.stopped)
+	for req := range b.queue {
+		var err error
+		if len(req.batch) > 0 {
+			err = b.sendWithRetries(req.batch)
+		}
+		if req.done != nil {
+			req.done <- err
+		} else {
+			b.report(err)
+		}
+	}
+}
+
+// sendWithRetries sends batch, retrying failures with exponential backoff
+func (b *Batcher[T]) sendWithRetries(batch []T) error {
+	backoff := b.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := b.send(batch)
+		if err == nil || attempt >= b.options.MaxRetries {
+			return err
+		}
+		if b.options.Retryable != nil && !b.options.Retryable(err) {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Flush sends any queued entries and waits until every batch handed to the sender
+// so far has been sent. It returns the error of the last batch, or, without an
+// ErrorHandler, of an earlier batch that failed in the background.
+func (b *Batcher[T]) Flush() error {
+	b.closeMu.RLock()
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		b.closeMu.RUnlock()
+		return nil
+	}
+	batch := b.takePendingLocked()
+	b.mu.Unlock()
+	done := make(chan error, 1)
+	b.queue <- batchRequest[T]{batch: batch, done: done}
+	b.closeMu.RUnlock()
+
+	err := <-done
+	b.mu.Lock()
+	if err == nil {
+		err = b.err
+	}
+	b.err = nil
+	b.mu.Unlock()
+	return err
+}
+
+// Close sends any queued entries and stops the sender. Calling it again is a no-op.
+func (b *Batcher[T]) Close() error {
+	err := b.Flush()
+	b.closeMu.Lock()
+	b.mu.Lock()
+	alreadyClosed := b.closed
+	b.closed = true
+	b.mu.Unlock()
+	if !alreadyClosed {
+		close(b.queue)
+	}
+	b.closeMu.Unlock()
+	<-b.stopped
+	return err
+}
+
+// BatchWriter is an output that groups entries and sends each group as one payload,
+// batching and retrying as described for Batcher
+type BatchWriter struct {
+	batcher   *Batcher[[]byte]
+	close     func() error
+	closeOnce sync.Once
+}
+
+// NewBatchWriter creates a BatchWriter that passes each batch payload to send
+func NewBatchWriter(send func(payload []byte) error, options BatchOptions) *BatchWriter {
+	jsonArray := options.JSONArray
+	return &BatchWriter{batcher: NewBatcher(func(batch [][]byte) error {
+		return send(payload(batch, jsonArray))
+	}, options)}
+}
+
+// NewHTTPWriter creates a BatchWriter that POSTs each batch to url, giving up on a
+// request after options.Timeout
+func NewHTTPWriter(url string, options BatchOptions) *BatchWriter {
+	contentType := "application/x-ndjson"
+	if options.JSONArray {
+		contentType = "application/json"
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSendTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	return NewBatchWriter(func(payload []byte) error {
+		resp, err := client.Post(url, contentType, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("log endpoint returned %s", resp.Status)
+		}
+		return nil
+	}, options)
+}
+
+// NewNetworkWriter connects to address on the named network (for example "tcp" or "udp")
+// and returns a BatchWriter that writes each batch to the connection. When a write
+// fails the connection is closed, and the next attempt dials a new one.
+func NewNetworkWriter(network, address string, options BatchOptions) (*BatchWriter, error) {
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSendTimeout
+	}
+	conn, err := net.DialTimeout(network, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	// conn is only used by the sender goroutine, and by close once it has stopped
+	w := NewBatchWriter(func(payload []byte) error {
+		if conn == nil {
+			redialed, err := net.DialTimeout(network, address, timeout)
+			if err != nil {
+				return err
+			}
+			conn = redialed
+		}
+		conn.SetWriteDeadline(time.Now().Add(timeout))
+		if _, err := conn.Write(payload); err != nil {
+			conn.Close()
+			conn = nil
+			return err
+		}
+		return nil
+	}, options)
+	w.close = func() error {
+		if conn == nil {
+			return nil
+		}
+		return conn.Close()
+	}
+	return w, nil
+}
+
+// Write queues a copy of p as one entry, handing the batch to the sender if it is full
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+	if err := w.batcher.Add(entry); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends any queued entries and waits until every batch handed to the sender
+// so far has been sent. It returns the error of the last batch, or, without an
+// ErrorHandler, of an earlier batch that failed in the background.
+func (w *BatchWriter) Flush() error {
+	return w.batcher.Flush()
+}
+
+func (w *BatchWriter) writesInBackground() {}
+
+// Close sends any queued entries, stops the sender and closes the underlying
+// connection, if any
+func (w *BatchWriter) Close() error {
+	err := w.batcher.Close()
+	w.closeOnce.Do(func() {
+		if w.close != nil {
+			err = errors.Join(err, w.close())
+		}
+	})
+	return err
+}
+
+// payload joins a batch of entries into newline-delimited form or a JSON array
+func payload(batch [][]byte, jsonArray bool) []byte {
+	var buf bytes.Buffer
+	if jsonArray {
+		buf.WriteByte('[')
+	}
+	for i, entry := range batch {
+		entry = bytes.TrimRight(entry, "\n")
+		if jsonArray && i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(entry)
+		if !jsonArray {
+			buf.WriteByte('\n')
+		}
+	}
+	if jsonArray {
+		buf.WriteByte(']')
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,289 @@
+package log_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// payloadRecorder collects the payloads sent by a BatchWriter
+type payloadRecorder struct {
+	mu       sync.Mutex
+	payloads []string
+	sent     chan struct{}
+}
+
+func newPayloadRecorder() *payloadRecorder {
+	return &payloadRecorder{sent: make(chan struct{}, 10)}
+}
+
+func (r *payloadRecorder) send(payload []byte) error {
+	r.mu.Lock()
+	r.payloads = append(r.payloads, string(payload))
+	r.mu.Unlock()
+	r.sent <- struct{}{}
+	return nil
+}
+
+func (r *payloadRecorder) Payloads() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.payloads...)
+}
+
+// TestBatchWriter_Size verifies that reaching the batch size sends the batch immediately
+func TestBatchWriter_Size(t *testing.T) {
+	recorder := newPayloadRecorder()
+	w := log.NewBatchWriter(recorder.send, log.BatchOptions{BatchSize: 3, BatchTimeout: time.Hour})
+
+	io.WriteString(w, "one\n")
+	io.WriteString(w, "two\n")
+	if len(recorder.Payloads()) != 0 {
+		t.Fatalf("Expected no payload before the batch is full, got %q", recorder.Payloads())
+	}
+	io.WriteString(w, "three\n")
+
+	select {
+	case <-recorder.sent:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the full batch to be sent")
+	}
+	payloads := recorder.Payloads()
+	if len(payloads) != 1 || payloads[0] != "one\ntwo\nthree\n" {
+		t.Errorf("Expected one newline-delimited payload, got %q", payloads)
+	}
+}
+
+// TestBatchWriter_Timeout verifies that a partial batch is sent after the batch timeout
+func TestBatchWriter_Timeout(t *testing.T) {
+	recorder := newPayloadRecorder()
+	w := log.NewBatchWriter(recorder.send, log.BatchOptions{BatchSize: 10, BatchTimeout: 20 * time.Millisecond})
+
+	io.WriteString(w, "one\n")
+	io.WriteString(w, "two\n")
+
+	select {
+	case <-recorder.sent:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the partial batch to be sent after the timeout")
+	}
+	payloads := recorder.Payloads()
+	if len(payloads) != 1 || payloads[0] != "one\ntwo\n" {
+		t.Errorf("Expected one payload with both entries, got %q", payloads)
+	}
+}
+
+// TestBatchWriter_Close verifies that closing the writer sends the partial batch
+func TestBatchWriter_Close(t *testing.T) {
+	recorder := newPayloadRecorder()
+	w := log.NewBatchWriter(recorder.send, log.BatchOptions{BatchSize: 10, BatchTimeout: time.Hour})
+
+	io.WriteString(w, "pending\n")
+	w.Close()
+
+	if payloads := recorder.Payloads(); len(payloads) != 1 || payloads[0] != "pending\n" {
+		t.Errorf("Expected the pending entry to be sent on Close, got %q", payloads)
+	}
+}
+
+// TestHTTPWriter verifies that batches are posted as a JSON array
+func TestHTTPWriter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	w := log.NewHTTPWriter(server.URL, log.BatchOptions{BatchSize: 2, JSONArray: true})
+	logger := log.NewLogger(w, log.INFO, &log.JSONFormatter{})
+	logger.Info("First")
+	logger.Info("Second")
+
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(<-bodies, &entries); err != nil {
+		t.Fatalf("Expected a JSON array payload, got error %v", err)
+	}
+	if len(entries) != 2 || entries[0]["message"] != "First" || entries[1]["message"] != "Second" {
+		t.Errorf("Expected both entries in order, got %v", entries)
+	}
+}
+
+// TestBatchWriter_Retry verifies that a failed batch is retried instead of being dropped
+func TestBatchWriter_Retry(t *testing.T) {
+	recorder := newPayloadRecorder()
+	failures := 2
+	send := func(payload []byte) error {
+		if failures > 0 {
+			failures--
+			return errors.New("temporarily unavailable")
+		}
+		return recorder.send(payload)
+	}
+	w := log.NewBatchWriter(send, log.BatchOptions{BatchTimeout: time.Hour, RetryBackoff: time.Millisecond})
+
+	io.WriteString(w, "retried\n")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Expected the batch to be sent after retrying, got %v", err)
+	}
+	if payloads := recorder.Payloads(); len(payloads) != 1 || payloads[0] != "retried\n" {
+		t.Errorf("Expected the entry to arrive once, got %q", payloads)
+	}
+}
+
+// TestBatchWriter_ErrorHandler verifies that a batch that fails in the background is reported to the ErrorHandler
+// option and that later entries are still sent
+func TestBatchWriter_ErrorHandler(t *testing.T) {
+	sendErr := errors.New("endpoint down")
+	recorder := newPayloadRecorder()
+	failures := 1
+	reported := make(chan error, 10)
+	w := log.NewBatchWriter(func(payload []byte) error {
+		if failures > 0 {
+			failures--
+			return sendErr
+		}
+		return recorder.send(payload)
+	}, log.BatchOptions{BatchSize: 1, MaxRetries: -1, ErrorHandler: func(err error) {
+		reported <- err
+	}})
+	defer w.Close()
+
+	io.WriteString(w, "lost\n")
+	if err := <-reported; err != sendErr {
+		t.Errorf("Expected %v to be reported, got %v", sendErr, err)
+	}
+	if _, err := io.WriteString(w, "next\n"); err != nil {
+		t.Errorf("Expected the next entry to be accepted, got %v", err)
+	}
+	<-recorder.sent
+	if payloads := recorder.Payloads(); len(payloads) != 1 || payloads[0] != "next\n" {
+		t.Errorf("Expected the next entry to be sent, got %q", payloads)
+	}
+}
+
+// TestBatchWriter_FlushReportsFailure verifies that without an ErrorHandler a background failure is returned by the
+// next Flush, without dropping the entries written after it
+func TestBatchWriter_FlushReportsFailure(t *testing.T) {
+	sendErr := errors.New("endpoint down")
+	recorder := newPayloadRecorder()
+	attempts := make(chan struct{}, 10)
+	failures := 1
+	w := log.NewBatchWriter(func(payload []byte) error {
+		defer func() { attempts <- struct{}{} }()
+		if failures > 0 {
+			failures--
+			return sendErr
+		}
+		return recorder.send(payload)
+	}, log.BatchOptions{BatchSize: 1, BatchTimeout: time.Hour, MaxRetries: -1})
+	defer w.Close()
+
+	io.WriteString(w, "lost\n")
+	<-attempts
+	if _, err := io.WriteString(w, "kept\n"); err != nil {
+		t.Errorf("Expected the entry after the failure to be accepted, got %v", err)
+	}
+	if err := w.Flush(); err != sendErr {
+		t.Errorf("Expected Flush to return %v, got %v", sendErr, err)
+	}
+	if payloads := recorder.Payloads(); len(payloads) != 1 || payloads[0] != "kept\n" {
+		t.Errorf("Expected the entry after the failure to be sent, got %q", payloads)
+	}
+	if err := w.Flush(); err != nil {
+		t.Errorf("Expected the failure to be returned once, got %v", err)
+	}
+}
+
+// TestNetworkWriter_Reconnect verifies that a dropped connection is replaced by a new one
+func TestNetworkWriter_Reconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	received := make(chan string, 10)
+	go func() {
+		first, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		first.Close() // Drop the first connection
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					received <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	w, err := log.NewNetworkWriter("tcp", listener.Addr().String(), log.BatchOptions{BatchTimeout: time.Hour, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer w.Close()
+
+	// Writes to the dropped connection may appear to succeed until the reset arrives
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		fmt.Fprintf(w, "message %d\n", i)
+		w.Flush()
+		select {
+		case line := <-received:
+			if !strings.HasPrefix(line, "message ") {
+				t.Errorf("Expected a message on the new connection, got %q", line)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("Expected the writer to reconnect")
+		}
+	}
+}
+
+// TestHTTPWriter_Unresponsive verifies that an endpoint that never answers neither blocks logging nor hangs Flush
+func TestHTTPWriter_Unresponsive(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	w := log.NewHTTPWriter(server.URL, log.BatchOptions{BatchSize: 1, MaxRetries: -1, Timeout: 100 * time.Millisecond})
+	logger := log.NewLogger(w, log.INFO, &log.DefaultFormatter{})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		logger.Info("Unanswered")
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Expected logging not to wait for the endpoint, took %v", elapsed)
+	}
+
+	start = time.Now()
+	if err := w.Flush(); err == nil {
+		t.Errorf("Expected a timeout error from Flush")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected Flush to give up after the timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,40 @@
+package log
+
+import "runtime/debug"
+
+// readBuildInfo returns the build information embedded in the binary; tests replace it
+var readBuildInfo = debug.ReadBuildInfo
+
+// SetBuildInfo makes the logger add "version" and "commit" fields to every entry.
+// Empty values are left out.
+func (l *Logger) SetBuildInfo(version, commit string) {
+	fields := Fields{}
+	if version != "" {
+		fields["version"] = version
+	}
+	if commit != "" {
+		fields["commit"] = commit
+	}
+	if len(fields) > 0 {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.defaults = (&Entry{Fields: l.defaults}).WithFields(fields).Fields
+	}
+}
+
+// SetBuildInfoFromBinary is SetBuildInfo with the main module's version and the VCS
+// revision that the Go toolchain embeds in binaries built from a module. Binaries
+// built without module support are left unchanged.
+func (l *Logger) SetBuildInfoFromBinary() {
+	info, ok := readBuildInfo()
+	if !ok {
+		return
+	}
+	var commit string
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			commit = setting.Value
+		}
+	}
+	l.SetBuildInfo(info.Main.Version, commit)
+}
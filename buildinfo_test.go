@@ -0,0 +1,45 @@
+package log_test
+
+import (
+	"runtime/debug"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestLogger_SetBuildInfo verifies that version and commit fields are added to every entry
+func TestLogger_SetBuildInfo(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetBuildInfo("1.4.0", "3f2a9c1")
+
+	logger.Info("First")
+	logger.WithField("version", "override").Info("Second")
+
+	entries := logger.Entries()
+	if entries[0].Fields["version"] != "1.4.0" || entries[0].Fields["commit"] != "3f2a9c1" {
+		t.Errorf("Expected version and commit fields, got %v", entries[0].Fields)
+	}
+	if entries[1].Fields["version"] != "override" || entries[1].Fields["commit"] != "3f2a9c1" {
+		t.Errorf("Expected a per-call field to override the version, got %v", entries[1].Fields)
+	}
+}
+
+// TestLogger_SetBuildInfoFromBinary verifies that the module version and VCS revision are read from the build info
+func TestLogger_SetBuildInfoFromBinary(t *testing.T) {
+	defer log.SetReadBuildInfo(func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main:     debug.Module{Path: "example.com/app", Version: "v2.1.0"},
+			Settings: []debug.BuildSetting{{Key: "vcs", Value: "git"}, {Key: "vcs.revision", Value: "9b8e7d6"}},
+		}, true
+	})()
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetBuildInfoFromBinary()
+
+	logger.Info("Started")
+
+	fields := logger.Entries()[0].Fields
+	if fields["version"] != "v2.1.0" || fields["commit"] != "9b8e7d6" {
+		t.Errorf("Expected version v2.1.0 and commit 9b8e7d6, got %v", fields)
+	}
+}
@@ -0,0 +1,227 @@
+package log
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCallerDepth bounds how many stack frames are inspected when locating the caller
+const maxCallerDepth = 32
+
+// packagePath is the import path of this package, used to recognise its own stack frames
+var packagePath = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	return functionPackage(runtime.FuncForPC(pc).Name())
+}()
+
+// mainModule is the module path of the running program and mainPackage the import path of
+// its main package, used by CallerModulePath
+var mainModule, mainPackage = func() (string, string) {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path, info.Path
+	}
+	return "", ""
+}()
+
+// functionPackage returns the import path of the package a fully qualified function name belongs to
+func functionPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// CallerFormat controls how formatters render the file of the caller
+type CallerFormat int
+
+// Caller formats
+const (
+	CallerShort      CallerFormat = iota // Base name of the file, e.g. "main.go"
+	CallerFullPath                       // Absolute path of the file as recorded at build time
+	CallerModulePath                     // Path relative to the main module root, e.g. "internal/db/conn.go"
+)
+
+// String returns the name of the caller format, as accepted by ParseCallerFormat
+func (f CallerFormat) String() string {
+	switch f {
+	case CallerFullPath:
+		return "fullpath"
+	case CallerModulePath:
+		return "modulepath"
+	default:
+		return "short"
+	}
+}
+
+// ParseCallerFormat converts a caller format name ("short", "fullpath" or "modulepath")
+// to the corresponding CallerFormat
+func ParseCallerFormat(format string) (CallerFormat, error) {
+	switch strings.ToLower(format) {
+	case "", "short":
+		return CallerShort, nil
+	case "fullpath":
+		return CallerFullPath, nil
+	case "modulepath":
+		return CallerModulePath, nil
+	default:
+		return CallerShort, fmt.Errorf("unknown caller format %q", format)
+	}
+}
+
+// maxCallerCacheSize bounds the number of program counters kept in the caller cache
+const maxCallerCacheSize = 4096
+
+// callerCache memoizes the frames resolved for each program counter so that repeated
+// logging from the same call site does not pay for symbol lookup every time
+var callerCache = struct {
+	sync.RWMutex
+	frames map[uintptr][]runtime.Frame
+}{frames: make(map[uintptr][]runtime.Frame)}
+
+// captureCaller returns the first stack frame outside this package,
+// starting skip frames above the function that calls captureCaller, or the frame outer
+// frames above that one. Because the logger's own frames are skipped by package rather
+// than by a fixed depth, the result is correct regardless of how many internal wrappers
+// the call passed through.
+func captureCaller(skip, outer int) (runtime.Frame, bool) {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	found := false
+	for _, pc := range pcs[:n] {
+		for _, frame := range cachedFrames(pc) {
+			if !found && skipsFrame(frame.Function) {
+				continue
+			}
+			found = true
+			if outer == 0 {
+				return frame, true
+			}
+			outer--
+		}
+	}
+	return runtime.Frame{}, false
+}
+
+// skippedPackages holds the function name prefixes of packages added with SkipCallerPackage
+var skippedPackages atomic.Pointer[[]string]
+
+// SkipCallerPackage makes caller lookup pass over frames from the package with the given
+// import path, as it does for this package's own frames. Adapters that log on behalf of
+// their caller, such as a bridge from another logging API, register their packages so
+// that entries report the adapter's caller.
+func SkipCallerPackage(pkg string) {
+	skippedPackagesMu.Lock()
+	defer skippedPackagesMu.Unlock()
+	var prefixes []string
+	if current := skippedPackages.Load(); current != nil {
+		prefixes = append(prefixes, *current...)
+	}
+	prefixes = append(prefixes, pkg+".")
+	skippedPackages.Store(&prefixes)
+}
+
+// skippedPackagesMu serializes SkipCallerPackage calls
+var skippedPackagesMu sync.Mutex
+
+// skipsFrame reports whether caller lookup passes over the frame of function
+func skipsFrame(function string) bool {
+	if strings.HasPrefix(function, packagePath+".") {
+		return true
+	}
+	if prefixes := skippedPackages.Load(); prefixes != nil {
+		for _, prefix := range *prefixes {
+			if strings.HasPrefix(function, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setCaller records the caller found by captureCaller on the entry
+func (e *Entry) setCaller(skip, outer int) {
+	if frame, ok := captureCaller(skip+1, outer); ok {
+		e.File, e.Line, e.Function = frame.File, frame.Line, frame.Function
+	}
+}
+
+// cachedFrames returns the frames for pc from the caller cache, resolving them on a miss.
+// The cache is cleared when it reaches maxCallerCacheSize.
+func cachedFrames(pc uintptr) []runtime.Frame {
+	callerCache.RLock()
+	frames, ok := callerCache.frames[pc]
+	callerCache.RUnlock()
+	if ok {
+		return frames
+	}
+
+	frames = resolveFrames(pc)
+	callerCache.Lock()
+	if len(callerCache.frames) >= maxCallerCacheSize {
+		callerCache.frames = make(map[uintptr][]runtime.Frame)
+	}
+	callerCache.frames[pc] = frames
+	callerCache.Unlock()
+	return frames
+}
+
+// resolveFrames returns the frames for a single program counter, innermost first.
+// A program counter yields more than one frame when calls were inlined at it.
+func resolveFrames(pc uintptr) []runtime.Frame {
+	var frames []runtime.Frame
+	iter := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// callerFile renders the entry's file in the given format, or "unknown" if it has none
+func callerFile(e Entry, format CallerFormat) string {
+	if e.File == "" {
+		return "unknown"
+	}
+	switch format {
+	case CallerFullPath:
+		return e.File
+	case CallerModulePath:
+		return modulePath(e)
+	default:
+		return filepath.Base(e.File)
+	}
+}
+
+// modulePath returns the entry's file as its package import path plus file name, with the
+// main module prefix removed. Files of a main package outside the main module, such as one
+// built from a file list, keep only their base name.
+func modulePath(e Entry) string {
+	// External test packages share the directory of the package they test
+	pkg := strings.TrimSuffix(functionPackage(e.Function), "_test")
+	if pkg == "" {
+		return e.File
+	}
+	if pkg == "main" {
+		// Functions of package main are named after the package, not its import path
+		if mainModule == "" || (mainPackage != mainModule && !strings.HasPrefix(mainPackage, mainModule+"/")) {
+			return filepath.Base(e.File)
+		}
+		pkg = mainPackage
+	}
+	file := pkg + "/" + filepath.Base(e.File)
+	if mainModule != "" {
+		if pkg == mainModule {
+			return filepath.Base(e.File)
+		}
+		file = strings.TrimPrefix(file, mainModule+"/")
+	}
+	return file
+}
@@ -0,0 +1,219 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_Caller verifies that every logging method reports the line in the test file that called it
+func TestLogger_Caller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.DEBUG, &log.DefaultFormatter{})
+
+	methods := map[string]func(...interface{}){
+		"Debug":  logger.Debug,
+		"Info":   logger.Info,
+		"Notice": logger.Notice,
+		"Warn":   logger.Warn,
+		"Error":  logger.Error,
+	}
+	for name, method := range methods {
+		buf.Reset()
+		_, _, line, _ := runtime.Caller(0)
+		method(name + " message")
+
+		expected := fmt.Sprintf("caller_test.go:%d", line+1)
+		if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s to report '%s', got %v", name, expected, buf.String())
+		}
+	}
+}
+
+// TestJSONFormatter_Caller verifies that the JSON formatter reports the test file as the caller
+func TestJSONFormatter_Caller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("Info message")
+
+	expected := fmt.Sprintf(`"file":"caller_test.go","line":%d`, line+1)
+	if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+		t.Errorf("Expected '%s' in output, got %v", expected, buf.String())
+	}
+}
+
+// TestDefaultFormatter_CallerFormat verifies each caller format against the calling test file
+func TestDefaultFormatter_CallerFormat(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	tests := []struct {
+		format   log.CallerFormat
+		expected string
+	}{
+		{log.CallerShort, "caller_test.go"},
+		{log.CallerFullPath, file},
+		{log.CallerModulePath, "caller_test.go"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{CallerFormat: tt.format})
+
+		_, _, line, _ := runtime.Caller(0)
+		logger.Info("Info message")
+
+		expected := fmt.Sprintf(" - %s:%d - ", tt.expected, line+1)
+		if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+			t.Errorf("Expected '%s' for caller format %d, got %v", expected, tt.format, buf.String())
+		}
+	}
+}
+
+// TestJSONFormatter_CallerModulePath verifies that module paths are relative to the main module root
+func TestJSONFormatter_CallerModulePath(t *testing.T) {
+	formatter := &log.JSONFormatter{CallerFormat: log.CallerModulePath}
+	tests := []struct {
+		function string
+		expected string
+	}{
+		{"github.com/pod32g/simple-logger/internal/db.Open", "internal/db/conn.go"},
+		{"github.com/pod32g/simple-logger/internal/db_test.TestOpen", "internal/db/conn.go"},
+		{"github.com/other/dependency.(*Client).Do", "github.com/other/dependency/conn.go"},
+	}
+	for _, tt := range tests {
+		output := formatter.FormatEntry(log.Entry{Level: log.INFO, File: "/src/app/internal/db/conn.go", Line: 7, Function: tt.function})
+
+		expected := fmt.Sprintf(`"file":%q`, tt.expected)
+		if !bytes.Contains([]byte(output), []byte(expected)) {
+			t.Errorf("Expected '%s' for function %s, got %v", expected, tt.function, output)
+		}
+	}
+}
+
+// TestJSONFormatter_CallerModulePathMain verifies that package main files are relative to the main module root
+func TestJSONFormatter_CallerModulePathMain(t *testing.T) {
+	formatter := &log.JSONFormatter{CallerFormat: log.CallerModulePath}
+	tests := []struct {
+		module   string
+		pkg      string
+		expected string
+	}{
+		{"example.com/app", "example.com/app/cmd/app", "cmd/app/main.go"},
+		{"example.com/app", "example.com/app", "main.go"},
+		{"example.com/app", "command-line-arguments", "main.go"},
+		{"", "", "main.go"},
+	}
+	for _, tt := range tests {
+		restore := log.SetMainPackage(tt.module, tt.pkg)
+		output := formatter.FormatEntry(log.Entry{Level: log.INFO, File: "/src/app/cmd/app/main.go", Line: 7, Function: "main.main"})
+		restore()
+
+		expected := fmt.Sprintf(`"file":%q`, tt.expected)
+		if !bytes.Contains([]byte(output), []byte(expected)) {
+			t.Errorf("Expected '%s' for main package %q, got %v", expected, tt.pkg, output)
+		}
+	}
+}
+
+// TestParseCallerFormat verifies caller format names and the error for unknown names
+func TestParseCallerFormat(t *testing.T) {
+	for name, expected := range map[string]log.CallerFormat{"short": log.CallerShort, "FullPath": log.CallerFullPath, "modulepath": log.CallerModulePath} {
+		if format, err := log.ParseCallerFormat(name); err != nil || format != expected {
+			t.Errorf("Expected %d for %q, got %d (%v)", expected, name, format, err)
+		}
+	}
+	if _, err := log.ParseCallerFormat("relative"); err == nil {
+		t.Errorf("Expected an error for an unknown caller format")
+	}
+}
+
+// TestLogger_SetCallerMinLevel verifies that only messages at or above the threshold carry file and line
+func TestLogger_SetCallerMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+	logger.SetCallerMinLevel(log.ERROR)
+
+	logger.Info("Info message")
+	_, _, line, _ := runtime.Caller(0)
+	logger.Error("Error message")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	if bytes.Contains(lines[0], []byte(`"file"`)) || bytes.Contains(lines[0], []byte(`"line"`)) {
+		t.Errorf("Expected no file or line for INFO, got %s", lines[0])
+	}
+	expected := fmt.Sprintf(`"file":"caller_test.go","line":%d`, line+1)
+	if !bytes.Contains(lines[1], []byte(expected)) {
+		t.Errorf("Expected '%s' for ERROR, got %s", expected, lines[1])
+	}
+}
+
+// BenchmarkLogger_CallerMinLevel measures INFO logging when the caller is only looked up for errors
+func BenchmarkLogger_CallerMinLevel(b *testing.B) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{})
+	logger.SetCallerMinLevel(log.ERROR)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("Benchmark message")
+	}
+}
+
+// BenchmarkCallerFrames_Uncached measures resolving a call site's frames without the cache
+func BenchmarkCallerFrames_Uncached(b *testing.B) {
+	pc := log.CallerPC()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		log.ResolveFrames(pc)
+	}
+}
+
+// BenchmarkCallerFrames_Cached measures resolving a call site's frames through the cache
+func BenchmarkCallerFrames_Cached(b *testing.B) {
+	pc := log.CallerPC()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		log.CachedFrames(pc)
+	}
+}
+
+// logInfo is a helper one frame above the logging call
+func logInfo(logger *log.Logger, message string) {
+	logger.WithCallerSkip(1).Info(message)
+}
+
+// logError is a helper two frames above the logging call
+func logError(logger *log.Logger, err error) {
+	reportError(logger, err)
+}
+
+func reportError(logger *log.Logger, err error) {
+	logger.WithCallerSkip(2).WithField("kind", "test").Error(err)
+}
+
+// TestEntry_WithCallerSkip verifies that helpers at different depths each report the line that called them
+func TestEntry_WithCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	_, _, line, _ := runtime.Caller(0)
+	logInfo(logger, "From the info helper")
+	logError(logger, fmt.Errorf("from the error helper"))
+	logger.WithCallerSkip(0).Info("Without a skip")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", buf.String())
+	}
+	for i, output := range lines {
+		expected := fmt.Sprintf("caller_test.go:%d", line+1+i)
+		if !bytes.Contains(output, []byte(expected)) {
+			t.Errorf("Expected '%s' in output, got %s", expected, output)
+		}
+	}
+}
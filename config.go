@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // CustomFormatter is an interface that users can implement to provide custom log formatting
@@ -15,20 +18,36 @@ type CustomFormatter interface {
 
 // LoggerConfig holds all configurable settings for the logger
 type LoggerConfig struct {
-	Level        LogLevel        `json:"level"`
-	Output       string          `json:"output"` // Can be "stdout", "stderr", or a filepath
-	Format       string          `json:"format"` // Can be "text", "json", or "custom"
-	Filepath     string          `json:"filepath"`
-	EnableCaller bool            `json:"enable_caller"`
-	Custom       CustomFormatter `json:"-"` // Custom formatter provided by the user
+	Level              LogLevel        `json:"level" yaml:"level"`
+	Output             string          `json:"output" yaml:"output"` // Can be "stdout", "stderr", "fd://N" for an inherited descriptor, a registered output, or a filepath
+	Format             string          `json:"format" yaml:"format"` // Can be "text", "json", "xml", "keyvalue", "console", "access", "auto", "custom", or a registered name; inferred from Output's extension if empty
+	Filepath           string          `json:"filepath" yaml:"filepath"`
+	ErrorOutput        string          `json:"error_output" yaml:"error_output"` // Also write ERROR and FATAL messages here, using the same values as Output
+	EnableCaller       bool            `json:"enable_caller" yaml:"enable_caller"`
+	CallerMinLevel     LogLevel        `json:"caller_min_level" yaml:"caller_min_level"`         // Only report the caller at or above this level, DEBUG by default
+	CallerFormat       string          `json:"caller_format" yaml:"caller_format"`               // Can be "short", "fullpath", or "modulepath"
+	TimePrecision      string          `json:"time_precision" yaml:"time_precision"`             // Can be "s", "ms", "us", or "ns"
+	InitialBufferSize  int             `json:"initial_buffer_size" yaml:"initial_buffer_size"`   // Initial message buffer capacity, DefaultInitialBufferSize if zero
+	Sequence           bool            `json:"sequence" yaml:"sequence"`                         // Add an increasing "seq" field to every entry
+	IncludeGoroutineID bool            `json:"include_goroutine_id" yaml:"include_goroutine_id"` // Add a "goroutine" field with the logging goroutine's ID
+	ShowDelta          bool            `json:"show_delta" yaml:"show_delta"`                     // Add a "delta" field with the time since the previous entry
+	DurationFormat     string          `json:"duration_format" yaml:"duration_format"`           // How the json format writes durations: "ns" or "string"
+	FlushOnError       bool            `json:"flush_on_error" yaml:"flush_on_error"`             // Flush buffered outputs after every ERROR or FATAL message
+	MaxFields          int             `json:"max_fields" yaml:"max_fields"`                     // Drop structured fields beyond this many, no limit if zero
+	MaxMessageBytes    int             `json:"max_message_bytes" yaml:"max_message_bytes"`       // Truncate longer messages, no limit if zero
+	FatalExitCode      int             `json:"fatal_exit_code" yaml:"fatal_exit_code"`           // Exit code after a FATAL message, DefaultFatalExitCode if zero
+	QuietAfter         int             `json:"quiet_after" yaml:"quiet_after"`                   // Write a repeated message this many times per DefaultQuietWindow, then summarize the rest, no limit if zero
+	Fields             Fields          `json:"fields" yaml:"fields"`                             // Static fields added to every entry, as with WithDefaults
+	Custom             CustomFormatter `json:"-" yaml:"-"`                                       // Custom formatter provided by the user
 }
 
-// DefaultConfig returns a LoggerConfig with default values
+// DefaultConfig returns a LoggerConfig with default values. Format is left empty so
+// that it is inferred from Output's extension, which gives text for stdout.
 func DefaultConfig() LoggerConfig {
 	return LoggerConfig{
 		Level:        INFO,
 		Output:       "stdout",
-		Format:       "text",
+		Format:       "",
 		Filepath:     "",
 		EnableCaller: true,
 	}
@@ -56,6 +75,18 @@ func LoadConfigFromEnv() LoggerConfig {
 		config.Format = strings.ToLower(format)
 	}
 
+	// Timestamp precision
+	timePrecision := os.Getenv("LOG_TIME_PRECISION")
+	if timePrecision != "" {
+		config.TimePrecision = timePrecision
+	}
+
+	// Caller format
+	callerFormat := os.Getenv("LOG_CALLER_FORMAT")
+	if callerFormat != "" {
+		config.CallerFormat = callerFormat
+	}
+
 	// Enable caller
 	enableCaller := os.Getenv("LOG_ENABLE_CALLER")
 	if enableCaller == "false" {
@@ -65,22 +96,70 @@ func LoadConfigFromEnv() LoggerConfig {
 	return config
 }
 
-// LoadConfigFromFile loads the logger configuration from a JSON file
+// LoadConfigFromFile loads the logger configuration from a JSON file, or from a YAML
+// file if the path ends in ".yaml" or ".yml"
 func LoadConfigFromFile(filePath string) (LoggerConfig, error) {
-	config := DefaultConfig()
 	file, err := os.Open(filePath)
 	if err != nil {
-		return config, err
+		return DefaultConfig(), err
 	}
 	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&config)
+	format := "json"
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	return LoadConfigFromReaderFormat(file, format)
+}
+
+// LoadConfigFromReader loads the logger configuration from JSON read from r.
+// Settings missing from the input keep their DefaultConfig values.
+func LoadConfigFromReader(r io.Reader) (LoggerConfig, error) {
+	return LoadConfigFromReaderFormat(r, "json")
+}
+
+// LoadConfigFromReaderFormat loads the logger configuration from r in the given
+// format, "json" or "yaml". Settings missing from the input keep their DefaultConfig values.
+func LoadConfigFromReaderFormat(r io.Reader, format string) (LoggerConfig, error) {
+	config := DefaultConfig()
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		err = json.NewDecoder(r).Decode(&config)
+	case "yaml", "yml":
+		err = yaml.NewDecoder(r).Decode(&config)
+	default:
+		err = fmt.Errorf("unknown config format %q", format)
+	}
+	return config, err
+}
+
+// timePrecision returns the configured timestamp precision, reporting unknown values on stderr
+func (config LoggerConfig) timePrecision() TimePrecision {
+	precision, err := ParseTimePrecision(config.TimePrecision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v", err)
+	}
+	return precision
+}
+
+// callerFormat returns the configured caller format, reporting unknown values on stderr
+func (config LoggerConfig) callerFormat() CallerFormat {
+	format, err := ParseCallerFormat(config.CallerFormat)
 	if err != nil {
-		return config, err
+		fmt.Fprintf(os.Stderr, "Error: %v", err)
 	}
+	return format
+}
 
-	return config, nil
+// durationFormat returns the configured duration format, reporting unknown values on stderr
+func (config LoggerConfig) durationFormat() DurationFormat {
+	format, err := ParseDurationFormat(config.DurationFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v", err)
+	}
+	return format
 }
 
 // UpdateLogLevel allows for dynamically updating the log level at runtime
@@ -93,57 +172,199 @@ func (config *LoggerConfig) UpdateLogFormat(format string) {
 	config.Format = strings.ToLower(format)
 }
 
-// ApplyConfig applies the loaded configuration to the Logger
+// expandEnv returns the config with environment variables such as $APP_NAME or
+// ${APP_NAME} in Output, ErrorOutput and Filepath replaced by their values. Unset
+// variables expand to the empty string, as with os.ExpandEnv.
+func (config LoggerConfig) expandEnv() LoggerConfig {
+	config.Output = os.ExpandEnv(config.Output)
+	config.Filepath = os.ExpandEnv(config.Filepath)
+	config.ErrorOutput = os.ExpandEnv(config.ErrorOutput)
+	return config
+}
+
+// ApplyConfig applies the loaded configuration to the Logger. Environment variables
+// in Output, ErrorOutput and Filepath are expanded first, see expandEnv.
 func ApplyConfig(config LoggerConfig) *Logger {
-	var output io.Writer = os.Stdout
-	if config.Output == "stderr" {
-		output = os.Stderr
-	} else if config.Output != "stdout" {
-		file, err := os.OpenFile(config.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error opening log file: %v", err)
-			output = os.Stdout
-		} else {
-			output = file
-		}
+	config = config.expandEnv()
+	output, closer, err := openOutput(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening log output: %v", err)
+		output, closer = os.Stdout, nil
 	}
 
 	// Select the appropriate formatter
-	var formatter Formatter
-	switch config.Format {
-	case "json":
-		formatter = &JSONFormatter{}
-	case "custom":
-		if config.Custom != nil {
-			formatter = config.Custom
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: Custom formatter is nil")
-			formatter = &DefaultFormatter{}
-		}
-	default:
-		formatter = &DefaultFormatter{}
-	}
+	formatter, formatName := newFormatter(config)
 
 	// Create and return the logger
 	logger := NewLogger(output, config.Level, formatter)
+	logger.output.closer = closer
+	logger.formatName = formatName
+	if err == nil {
+		logger.output.name = config.Output
+	}
+	logger.SetInitialBufferSize(config.InitialBufferSize)
+	logger.SetMaxMessageBytes(config.MaxMessageBytes)
+	logger.SetMaxFields(config.MaxFields)
+	logger.SetCallerMinLevel(config.CallerMinLevel)
+	logger.SetSequence(config.Sequence)
+	logger.SetIncludeGoroutineID(config.IncludeGoroutineID)
+	logger.SetShowDelta(config.ShowDelta)
+	logger.SetQuietAfter(config.QuietAfter, DefaultQuietWindow)
+	logger.SetFatalExitCode(config.FatalExitCode)
+	if len(config.Fields) > 0 {
+		logger.defaults = (&Entry{}).WithFields(config.Fields).Fields
+	}
+	if config.FlushOnError {
+		logger.SetFlushLevel(ERROR)
+	}
 
 	return logger
 }
 
-// parseLogLevel converts a string representation of a log level to the corresponding LogLevel
-func parseLogLevel(level string) LogLevel {
+// Reconfigure applies config to the logger in place, so every holder of the logger sees
+// the new level, output and formatter. A file previously opened by the logger is closed.
+// If the configured output cannot be opened the logger is left unchanged.
+// Environment variables in Output, ErrorOutput and Filepath are expanded as in ApplyConfig.
+// Default fields from config.Fields are only installed by ApplyConfig; Reconfigure keeps
+// the logger's current ones.
+func (l *Logger) Reconfigure(config LoggerConfig) error {
+	config = config.expandEnv()
+	output, closer, err := openOutput(config)
+	if err != nil {
+		return err
+	}
+	formatter, formatName := newFormatter(config)
+	bufferSize := config.InitialBufferSize
+	if bufferSize < 1 {
+		bufferSize = DefaultInitialBufferSize
+	}
+
+	previous := l.output.replace(output, closer, config.Output)
+	l.mu.Lock()
+	l.formatter = formatter
+	l.formatName = formatName
+	l.bufferSize = bufferSize
+	l.maxMessageBytes = config.MaxMessageBytes
+	l.maxFields = config.MaxFields
+	l.callerMinLevel = config.CallerMinLevel
+	l.withSequence = config.Sequence
+	l.withGoroutineID = config.IncludeGoroutineID
+	l.showDelta = config.ShowDelta
+	l.quiet = newQuietFilter(config.QuietAfter, DefaultQuietWindow)
+	l.fatalExitCode = config.FatalExitCode
+	if l.fatalExitCode == 0 {
+		l.fatalExitCode = DefaultFatalExitCode
+	}
+	l.flushLevel = OFF
+	if config.FlushOnError {
+		l.flushLevel = ERROR
+	}
+	l.SetLevel(config.Level)
+	l.mu.Unlock()
+
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// openOutput opens the writer named by config.Output: stdout, stderr, a registered
+// output, a file descriptor or a file path. The returned closer is the opened output,
+// or nil for stdout and stderr, which the logger does not own. With config.ErrorOutput
+// set, the output is a LevelWriter that also writes ERROR and FATAL messages there.
+func openOutput(config LoggerConfig) (io.Writer, io.Closer, error) {
+	if config.ErrorOutput != "" {
+		return openWithErrorOutput(config)
+	}
+	switch config.Output {
+	case "stderr":
+		return os.Stderr, nil, nil
+	case "stdout":
+		return os.Stdout, nil, nil
+	}
+	if factory, ok := lookupOutput(config.Output); ok {
+		output, err := factory(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		closer, _ := output.(io.Closer)
+		return output, closer, nil
+	}
+
+	var file *os.File
+	var err error
+	if strings.HasPrefix(config.Output, fdPrefix) {
+		file, err = openDescriptor(config.Output)
+	} else {
+		file, err = openLogFile(config.Output)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, file, nil
+}
+
+// openWithErrorOutput opens config.Output and config.ErrorOutput and combines them in a
+// LevelWriter copying ERROR and FATAL messages to the error output
+func openWithErrorOutput(config LoggerConfig) (io.Writer, io.Closer, error) {
+	errorConfig := config
+	errorConfig.Output, errorConfig.ErrorOutput = config.ErrorOutput, ""
+	config.ErrorOutput = ""
+	output, closer, err := openOutput(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	errorOutput, errorCloser, err := openOutput(errorConfig)
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, nil, err
+	}
+	return NewLevelWriter(output, ERROR, errorOutput), closers{closer, errorCloser}, nil
+}
+
+// logFileMode is the permission used when a log file is created
+const logFileMode = 0644
+
+// openLogFile opens path for appending, creating the file and its parent directories if needed
+func openLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFileMode)
+}
+
+// ParseLevel converts a case-insensitive level name such as "debug" or "WARN" to the
+// corresponding LogLevel. Common syslog-style aliases such as "warning", "err", and
+// "critical" are accepted. It returns an error if the name is not a known level.
+func ParseLevel(level string) (LogLevel, error) {
 	switch strings.ToUpper(level) {
 	case "DEBUG":
-		return DEBUG
-	case "INFO":
-		return INFO
-	case "WARN":
-		return WARN
-	case "ERROR":
-		return ERROR
-	case "FATAL":
-		return FATAL
+		return DEBUG, nil
+	case "INFO", "INFORMATIONAL":
+		return INFO, nil
+	case "NOTICE":
+		return NOTICE, nil
+	case "WARN", "WARNING":
+		return WARN, nil
+	case "ERROR", "ERR":
+		return ERROR, nil
+	case "FATAL", "CRIT", "CRITICAL":
+		return FATAL, nil
+	case "OFF", "SILENT":
+		return OFF, nil
 	default:
+		return INFO, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// parseLogLevel converts a string representation of a log level to the corresponding LogLevel,
+// falling back to INFO for unknown values
+func parseLogLevel(level string) LogLevel {
+	parsed, err := ParseLevel(level)
+	if err != nil {
 		return INFO // Default log level
 	}
+	return parsed
 }
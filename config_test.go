@@ -0,0 +1,518 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// readLogFile returns the contents of a log file written during a test
+func readLogFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	return string(data)
+}
+
+// TestApplyConfig_EnableCaller verifies that the file and line fields are present when EnableCaller is true
+func TestApplyConfig_EnableCaller(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:        log.INFO,
+		Output:       path,
+		Format:       "json",
+		EnableCaller: true,
+	})
+
+	logger.Info("Caller enabled")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(readLogFile(t, path)), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if _, ok := entry["file"]; !ok {
+		t.Errorf("Expected 'file' field in output, got %v", entry)
+	}
+	if _, ok := entry["line"]; !ok {
+		t.Errorf("Expected 'line' field in output, got %v", entry)
+	}
+}
+
+// TestApplyConfig_DisableCaller verifies that the file and line fields are absent when EnableCaller is false
+func TestApplyConfig_DisableCaller(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:        log.INFO,
+		Output:       path,
+		Format:       "json",
+		EnableCaller: false,
+	})
+
+	logger.Info("Caller disabled")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(readLogFile(t, path)), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if _, ok := entry["file"]; ok {
+		t.Errorf("Expected no 'file' field in output, got %v", entry)
+	}
+	if _, ok := entry["line"]; ok {
+		t.Errorf("Expected no 'line' field in output, got %v", entry)
+	}
+}
+
+// TestApplyConfig_DisableCallerText verifies that the text format omits file:line when EnableCaller is false
+func TestApplyConfig_DisableCallerText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:        log.INFO,
+		Output:       path,
+		Format:       "text",
+		EnableCaller: false,
+	})
+
+	logger.Info("Caller disabled")
+
+	if output := readLogFile(t, path); strings.Contains(output, ".go:") {
+		t.Errorf("Expected no caller information in output, got %v", output)
+	}
+}
+
+// TestLoadConfigFromEnv_Notice verifies that LOG_LEVEL=notice resolves to the NOTICE level
+func TestLoadConfigFromEnv_Notice(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "notice")
+
+	config := log.LoadConfigFromEnv()
+
+	if config.Level != log.NOTICE {
+		t.Errorf("Expected level NOTICE, got %v", config.Level)
+	}
+}
+
+// TestLoadConfigFromEnv_Off verifies that LOG_LEVEL=off and LOG_LEVEL=silent resolve to the OFF level
+func TestLoadConfigFromEnv_Off(t *testing.T) {
+	for _, value := range []string{"off", "silent"} {
+		t.Setenv("LOG_LEVEL", value)
+
+		config := log.LoadConfigFromEnv()
+
+		if config.Level != log.OFF {
+			t.Errorf("Expected level OFF for %q, got %v", value, config.Level)
+		}
+	}
+}
+
+// TestParseLevel verifies that every level name is parsed case-insensitively
+func TestParseLevel(t *testing.T) {
+	levels := map[string]log.LogLevel{
+		"debug":  log.DEBUG,
+		"Info":   log.INFO,
+		"NOTICE": log.NOTICE,
+		"warn":   log.WARN,
+		"ERROR":  log.ERROR,
+		"fatal":  log.FATAL,
+		"off":    log.OFF,
+		"Silent": log.OFF,
+	}
+	for name, expected := range levels {
+		level, err := log.ParseLevel(name)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", name, err)
+		}
+		if level != expected {
+			t.Errorf("Expected %v for %q, got %v", expected, name, level)
+		}
+	}
+}
+
+// TestParseLevel_Unknown verifies that an unknown level name is an error
+func TestParseLevel_Unknown(t *testing.T) {
+	if _, err := log.ParseLevel("verbose-ish"); err == nil {
+		t.Errorf("Expected an error for an unknown level name, got nil")
+	}
+}
+
+// TestLogger_SetLevelFromString verifies that the level is changed for valid names and kept for invalid ones
+func TestLogger_SetLevelFromString(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+
+	if err := logger.SetLevelFromString("debug"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	logger.Debug("Debug message")
+	logger.AssertLogged(t, log.DEBUG, "Debug message")
+
+	if err := logger.SetLevelFromString("garbage"); err == nil {
+		t.Errorf("Expected an error for an unknown level name, got nil")
+	}
+	logger.Debug("Still debug")
+	logger.AssertLogged(t, log.DEBUG, "Still debug")
+}
+
+// TestParseLevel_Aliases verifies that syslog-style aliases map to the canonical levels
+func TestParseLevel_Aliases(t *testing.T) {
+	aliases := map[string]log.LogLevel{
+		"informational": log.INFO,
+		"WARNING":       log.WARN,
+		"err":           log.ERROR,
+		"CRIT":          log.FATAL,
+		"critical":      log.FATAL,
+	}
+	for name, expected := range aliases {
+		level, err := log.ParseLevel(name)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", name, err)
+		}
+		if level != expected {
+			t.Errorf("Expected %v for %q, got %v", expected, name, level)
+		}
+	}
+}
+
+// TestLoadConfigFromEnv_Alias verifies that LOG_LEVEL accepts aliases and the canonical name is used for output
+func TestLoadConfigFromEnv_Alias(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warning")
+
+	config := log.LoadConfigFromEnv()
+
+	if config.Level != log.WARN || config.Level.String() != "WARN" {
+		t.Errorf("Expected level WARN, got %v", config.Level)
+	}
+}
+
+// TestApplyConfig_FileDescriptor verifies that an "fd://N" output writes to the inherited descriptor
+func TestApplyConfig_FileDescriptor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file descriptor outputs are not supported on windows")
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:  log.INFO,
+		Output: fmt.Sprintf("fd://%d", w.Fd()),
+		Format: "text",
+	})
+	logger.Info("Through the descriptor")
+
+	buf := make([]byte, 256)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("Failed to read from pipe: %v", err)
+	}
+	if !strings.Contains(string(buf[:n]), "Through the descriptor") {
+		t.Errorf("Expected the message on the pipe's read end, got %q", buf[:n])
+	}
+
+	// The descriptor is inherited, so shutting the logger down must leave it open
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error from Shutdown, got %v", err)
+	}
+	if _, err := w.Write([]byte("Still open\n")); err != nil {
+		t.Fatalf("Expected the inherited descriptor to stay open after Shutdown, got %v", err)
+	}
+	n, err = r.Read(buf)
+	if err != nil || string(buf[:n]) != "Still open\n" {
+		t.Errorf("Expected the write after Shutdown on the pipe, got %q (%v)", buf[:n], err)
+	}
+}
+
+// TestApplyConfig_ExpandEnv verifies that environment variables in the output path are expanded
+func TestApplyConfig_ExpandEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_NAME", "checkout")
+
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:  log.INFO,
+		Output: filepath.Join(dir, "${APP_NAME}", "app.log"),
+		Format: "text",
+	})
+	logger.Info("Expanded path")
+	logger.Shutdown(context.Background())
+
+	if content := readLogFile(t, filepath.Join(dir, "checkout", "app.log")); !strings.Contains(content, "Expanded path") {
+		t.Errorf("Expected the message in the expanded path, got %q", content)
+	}
+}
+
+// TestApplyConfig_ErrorOutput verifies that errors are also written to the error output
+func TestApplyConfig_ErrorOutput(t *testing.T) {
+	dir := t.TempDir()
+	appLog, errorLog := filepath.Join(dir, "app.log"), filepath.Join(dir, "errors.log")
+
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:       log.INFO,
+		Output:      appLog,
+		ErrorOutput: errorLog,
+		Format:      "text",
+	})
+	logger.Info("Request served")
+	logger.Error("Request failed")
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to close the log files: %v", err)
+	}
+
+	app := readLogFile(t, appLog)
+	if !strings.Contains(app, "[INFO] Request served") || !strings.Contains(app, "[ERROR] Request failed") {
+		t.Errorf("Expected both messages in the main file, got %q", app)
+	}
+	errors := readLogFile(t, errorLog)
+	if strings.Contains(errors, "Request served") || !strings.Contains(errors, "[ERROR] Request failed") {
+		t.Errorf("Expected only the error in the error file, got %q", errors)
+	}
+}
+
+// TestApplyConfig_FormatFromExtension verifies that an unset format is inferred from the output's extension
+func TestApplyConfig_FormatFromExtension(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file     string
+		format   string
+		expected string
+	}{
+		{"app.json", "", "json"},
+		{"app.ndjson", "", "json"},
+		{"app.logfmt", "", "keyvalue"},
+		{"app.log", "", "text"},
+		{"app.JSON", "", "json"},
+		{"explicit.json", "text", "text"},
+	}
+	for _, tt := range tests {
+		logger := log.ApplyConfig(log.LoggerConfig{Level: log.INFO, Output: filepath.Join(dir, tt.file), Format: tt.format})
+		if format := logger.Config().Format; format != tt.expected {
+			t.Errorf("Expected format %q for %s with format %q, got %q", tt.expected, tt.file, tt.format, format)
+		}
+		logger.Info("Inferred format")
+		logger.Shutdown(context.Background())
+	}
+
+	if content := readLogFile(t, filepath.Join(dir, "app.json")); !strings.HasPrefix(content, "{") {
+		t.Errorf("Expected JSON in app.json, got %q", content)
+	}
+	if content := readLogFile(t, filepath.Join(dir, "explicit.json")); strings.HasPrefix(content, "{") {
+		t.Errorf("Expected the explicit text format in explicit.json, got %q", content)
+	}
+}
+
+// TestLogger_SetOutputFile verifies that switching the output file sends later messages to the new file
+func TestLogger_SetOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "nested", "dir", "second.log")
+
+	logger := log.NewLogger(os.Stdout, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	if err := logger.SetOutputFile(first); err != nil {
+		t.Fatalf("Failed to set output file: %v", err)
+	}
+	logger.Info("Before the switch")
+	if err := logger.SetOutputFile(second); err != nil {
+		t.Fatalf("Failed to switch output file: %v", err)
+	}
+	logger.Info("After the switch")
+	logger.Shutdown(context.Background())
+
+	if content := readLogFile(t, first); !strings.Contains(content, "Before the switch") || strings.Contains(content, "After the switch") {
+		t.Errorf("Expected only the first message in the first file, got %q", content)
+	}
+	if content := readLogFile(t, second); !strings.Contains(content, "After the switch") || strings.Contains(content, "Before the switch") {
+		t.Errorf("Expected only the second message in the second file, got %q", content)
+	}
+}
+
+// TestLogger_Reconfigure verifies that a new config takes effect on the same logger instance
+func TestLogger_Reconfigure(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.log")
+	after := filepath.Join(dir, "after.log")
+
+	logger := log.ApplyConfig(log.LoggerConfig{Level: log.INFO, Output: before, Format: "text"})
+	shared := logger
+	logger.Debug("Filtered before")
+	logger.Info("Text message")
+
+	if err := logger.Reconfigure(log.LoggerConfig{Level: log.DEBUG, Output: after, Format: "json"}); err != nil {
+		t.Fatalf("Failed to reconfigure: %v", err)
+	}
+	shared.Debug("JSON debug message")
+	logger.Shutdown(context.Background())
+
+	if content := readLogFile(t, before); !strings.Contains(content, "Text message") || strings.Contains(content, "Filtered before") {
+		t.Errorf("Expected only the INFO text message before reconfiguring, got %q", content)
+	}
+	content := readLogFile(t, after)
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &entry); err != nil {
+		t.Fatalf("Expected JSON after reconfiguring, got error %v for %q", err, content)
+	}
+	if entry["level"] != "DEBUG" || entry["message"] != "JSON debug message" {
+		t.Errorf("Expected the DEBUG message in JSON, got %v", entry)
+	}
+}
+
+// TestLogger_ReconfigureConcurrent verifies that reconfiguring while other goroutines log is race free
+func TestLogger_ReconfigureConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.ApplyConfig(log.LoggerConfig{Level: log.INFO, Output: path, Format: "text"})
+	defer logger.Shutdown(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("Concurrent message")
+			}
+		}()
+	}
+	for _, format := range []string{"json", "text", "xml"} {
+		if err := logger.Reconfigure(log.LoggerConfig{Level: log.INFO, Output: path, Format: format}); err != nil {
+			t.Errorf("Failed to reconfigure: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+// TestLoadConfigFromReader verifies that a JSON config is decoded from a reader over defaults
+func TestLoadConfigFromReader(t *testing.T) {
+	config, err := log.LoadConfigFromReader(strings.NewReader(`{"level": 3, "format": "json", "output": "stderr"}`))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Level != log.WARN || config.Format != "json" || config.Output != "stderr" {
+		t.Errorf("Expected level WARN, format json and output stderr, got %+v", config)
+	}
+	if !config.EnableCaller {
+		t.Errorf("Expected EnableCaller to keep its default, got %+v", config)
+	}
+}
+
+// TestLoadConfigFromReader_FormatFromExtension verifies that a config without a format infers it from the output's extension
+func TestLoadConfigFromReader_FormatFromExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	config, err := log.LoadConfigFromReader(strings.NewReader(fmt.Sprintf(`{"output": %q}`, path)))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Format != "" {
+		t.Errorf("Expected the format to be left empty, got %q", config.Format)
+	}
+
+	logger := log.ApplyConfig(config)
+	logger.Info("Inferred format")
+	logger.Shutdown(context.Background())
+	if format := logger.Config().Format; format != "json" {
+		t.Errorf("Expected format json, got %q", format)
+	}
+	if content := readLogFile(t, path); !strings.HasPrefix(content, "{") {
+		t.Errorf("Expected JSON in app.json, got %q", content)
+	}
+}
+
+// TestApplyConfig_Fields verifies that fields declared in a config file are added to every line
+func TestApplyConfig_Fields(t *testing.T) {
+	config, err := log.LoadConfigFromReaderFormat(strings.NewReader("format: keyvalue\nenable_caller: false\nfields:\n  service: api\n  region: eu-west-1\n"), "yaml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	logger := log.ApplyConfig(config)
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+
+	logger.Info("First")
+	logger.WithField("service", "override").Warn("Second")
+	logger.WithDefaults(log.Fields{"component": "db"}).Error("Third")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "region=eu-west-1") {
+			t.Errorf("Expected the configured region on every line, got %s", line)
+		}
+	}
+	if !strings.Contains(lines[0], "service=api") || !strings.Contains(lines[2], "service=api component=db") {
+		t.Errorf("Expected the configured service, got %q", lines)
+	}
+	if !strings.Contains(lines[1], "service=override") {
+		t.Errorf("Expected an entry field to override the configured one, got %s", lines[1])
+	}
+	if fields := logger.Config().Fields; fields["service"] != "api" || fields["region"] != "eu-west-1" {
+		t.Errorf("Expected Config to report the configured fields, got %v", fields)
+	}
+}
+
+// TestLoadConfigFromReaderFormat verifies YAML decoding and the error for unknown formats
+func TestLoadConfigFromReaderFormat(t *testing.T) {
+	config, err := log.LoadConfigFromReaderFormat(strings.NewReader("level: 4\nformat: xml\ntime_precision: ms\n"), "yaml")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Level != log.ERROR || config.Format != "xml" || config.TimePrecision != "ms" {
+		t.Errorf("Expected level ERROR, format xml and precision ms, got %+v", config)
+	}
+
+	if _, err := log.LoadConfigFromReaderFormat(strings.NewReader(""), "toml"); err == nil {
+		t.Errorf("Expected an error for an unknown config format")
+	}
+}
+
+// TestLogger_Config verifies that Config reports the settings of a logger created with ApplyConfig
+func TestLogger_Config(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	applied := log.LoggerConfig{
+		Level:           log.WARN,
+		Output:          path,
+		Format:          "json",
+		EnableCaller:    true,
+		CallerFormat:    "modulepath",
+		TimePrecision:   "ms",
+		MaxMessageBytes: 1024,
+	}
+	logger := log.ApplyConfig(applied)
+	defer logger.Shutdown(context.Background())
+
+	config := logger.Config()
+	if config.Level != applied.Level || config.Output != applied.Output || config.Format != applied.Format {
+		t.Errorf("Expected level, output and format %v %q %q, got %v %q %q", applied.Level, applied.Output, applied.Format, config.Level, config.Output, config.Format)
+	}
+	if config.EnableCaller != applied.EnableCaller || config.CallerFormat != applied.CallerFormat || config.TimePrecision != applied.TimePrecision {
+		t.Errorf("Expected caller %v %q and precision %q, got %v %q %q", applied.EnableCaller, applied.CallerFormat, applied.TimePrecision, config.EnableCaller, config.CallerFormat, config.TimePrecision)
+	}
+	if config.MaxMessageBytes != applied.MaxMessageBytes {
+		t.Errorf("Expected MaxMessageBytes %d, got %d", applied.MaxMessageBytes, config.MaxMessageBytes)
+	}
+}
+
+// TestLogger_ConfigDirect verifies that Config describes outputs and formatters set without a config
+func TestLogger_ConfigDirect(t *testing.T) {
+	logger := log.NewLogger(os.Stderr, log.DEBUG, &log.XMLFormatter{DisableCaller: true})
+
+	config := logger.Config()
+	if config.Output != "stderr" || config.Format != "xml" || config.EnableCaller {
+		t.Errorf("Expected stderr, xml and no caller, got %q %q %v", config.Output, config.Format, config.EnableCaller)
+	}
+
+	logger.SetFormatter(&MyCustomFormatter{})
+	if config := logger.Config(); config.Format != "custom" || config.Custom == nil {
+		t.Errorf("Expected a custom format with the formatter attached, got %q %v", config.Format, config.Custom)
+	}
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ANSI color codes used by ConsoleFormatter
+const (
+	colorReset  = "\x1b[0m"
+	colorGray   = "\x1b[90m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+	colorYellow = "\x1b[33m"
+	colorRed    = "\x1b[31m"
+	colorPurple = "\x1b[35m"
+)
+
+// levelColor returns the ANSI color used for a level
+func levelColor(level LogLevel) string {
+	switch level {
+	case DEBUG:
+		return colorGray
+	case INFO:
+		return colorBlue
+	case NOTICE:
+		return colorCyan
+	case WARN:
+		return colorYellow
+	case ERROR:
+		return colorRed
+	case FATAL:
+		return colorPurple
+	default:
+		return ""
+	}
+}
+
+// consoleLevelWidth is the width the console pads level names to, the longest level name
+var consoleLevelWidth = func() int {
+	width := 0
+	for _, level := range AllLevels() {
+		width = max(width, len(level.String()))
+	}
+	return width
+}()
+
+// processStart is the default start of ConsoleFormatter's relative times
+var processStart = time.Now()
+
+// ConsoleFormatter is a human-friendly text formatter for interactive terminals.
+// It colors the level and writes a short time, the caller, the message, and any fields.
+// Level names are padded to the same width so that messages line up in a column.
+type ConsoleFormatter struct {
+	DisableCaller bool         // Skip the caller lookup and omit file:line from the output
+	CallerFormat  CallerFormat // How the caller's file is rendered, base name by default
+	DisableColors bool         // Write plain text without ANSI color codes
+	ColorLine     bool         // Color the whole line in the level's color, not just the level
+
+	// RelativeTime writes the time elapsed since Start, such as "+0.123s", instead of the
+	// time of day. A zero Start means the time the program started.
+	RelativeTime bool
+	Start        time.Time
+}
+
+func (f *ConsoleFormatter) Format(level LogLevel, message string) string {
+	return f.FormatEntry(newEntry(level, message, f.reportsCaller()))
+}
+
+// FormatEntry formats an entry as a single colored line
+func (f *ConsoleFormatter) FormatEntry(e Entry) string {
+	var b strings.Builder
+	colorLine := f.ColorLine && !f.DisableColors
+	if colorLine {
+		b.WriteString(levelColor(e.Level))
+	}
+	b.WriteString(f.timestamp(e))
+	b.WriteByte(' ')
+	level := e.Level.String()
+	if f.DisableColors || colorLine {
+		b.WriteString(level)
+	} else {
+		b.WriteString(levelColor(e.Level) + level + colorReset)
+	}
+	b.WriteString(strings.Repeat(" ", max(consoleLevelWidth-len(level), 0)))
+	if !f.DisableCaller && !e.skipCaller {
+		fmt.Fprintf(&b, " %s:%d", callerFile(e, f.CallerFormat), e.Line)
+	}
+	b.WriteByte(' ')
+	b.WriteString(e.Message)
+	b.WriteString(textFields(e.Fields))
+	if colorLine {
+		b.WriteString(colorReset)
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// timestamp renders the entry's time of day, or its time since Start with RelativeTime
+func (f *ConsoleFormatter) timestamp(e Entry) string {
+	if !f.RelativeTime {
+		return e.Time.Format("15:04:05.000")
+	}
+	start := f.Start
+	if start.IsZero() {
+		start = processStart
+	}
+	return fmt.Sprintf("+%.3fs", e.Time.Sub(start).Seconds())
+}
+
+func (f *ConsoleFormatter) reportsCaller() bool {
+	return !f.DisableCaller
+}
+
+// isTerminal reports whether f is attached to a terminal; tests replace it to force either branch
+var isTerminal = func(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// autoFormat picks the format used for Format "auto". The APP_ENV variable, or GO_ENV
+// if APP_ENV is unset, takes precedence: "production"/"prod" selects json and
+// "development"/"dev" selects console. Otherwise console is used when the configured
+// output is stdout or stderr attached to a terminal, and json in every other case.
+func autoFormat(config LoggerConfig) string {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = os.Getenv("GO_ENV")
+	}
+	switch strings.ToLower(env) {
+	case "production", "prod":
+		return "json"
+	case "development", "dev":
+		return "console"
+	}
+
+	switch config.Output {
+	case "stdout", "":
+		if isTerminal(os.Stdout) {
+			return "console"
+		}
+	case "stderr":
+		if isTerminal(os.Stderr) {
+			return "console"
+		}
+	}
+	return "json"
+}
@@ -0,0 +1,143 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestConsoleFormatter verifies the colored console layout with caller and fields
+func TestConsoleFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	clock := time.Date(2024, 5, 1, 12, 30, 45, 123000000, time.UTC)
+	logger := log.NewLogger(&buf, log.INFO, &log.ConsoleFormatter{})
+	logger.SetClock(fixedClock{clock})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.WithField("user", "alice").Error("Console message")
+
+	expected := fmt.Sprintf("12:30:45.123 \x1b[31mERROR\x1b[0m  console_test.go:%d Console message user=alice\n", line+1)
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+// TestConsoleFormatter_DisableColors verifies that no ANSI codes are written when colors are disabled
+func TestConsoleFormatter_DisableColors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.ConsoleFormatter{DisableColors: true, DisableCaller: true})
+
+	logger.Info("Plain message")
+
+	if strings.Contains(buf.String(), "\x1b[") || !strings.HasSuffix(buf.String(), " INFO   Plain message\n") {
+		t.Errorf("Expected plain console output, got %q", buf.String())
+	}
+}
+
+// TestConsoleFormatter_LevelPadding verifies that messages start in the same column whatever the level
+func TestConsoleFormatter_LevelPadding(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.DEBUG, &log.ConsoleFormatter{DisableColors: true, DisableCaller: true})
+
+	logger.Debug("Aligned")
+	logger.Info("Aligned")
+	logger.Notice("Aligned")
+	logger.Warn("Aligned")
+	logger.Error("Aligned")
+
+	expected := []string{" DEBUG  Aligned", " INFO   Aligned", " NOTICE Aligned", " WARN   Aligned", " ERROR  Aligned"}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("Expected line ending in %q, got %q", expected[i], line)
+		}
+		if column := strings.Index(line, "Aligned"); column != len("12:30:45.123 NOTICE ") {
+			t.Errorf("Expected the message at column %d, got %d in %q", len("12:30:45.123 NOTICE "), column, line)
+		}
+	}
+}
+
+// TestConsoleFormatter_ColorLine verifies that the whole line is wrapped in the level's color
+func TestConsoleFormatter_ColorLine(t *testing.T) {
+	var buf bytes.Buffer
+	clock := time.Date(2024, 5, 1, 12, 30, 45, 123000000, time.UTC)
+	logger := log.NewLogger(&buf, log.INFO, &log.ConsoleFormatter{ColorLine: true, DisableCaller: true})
+	logger.SetClock(fixedClock{clock})
+
+	logger.WithField("disk", "sda").Warn("Disk almost full")
+
+	expected := "\x1b[33m12:30:45.123 WARN   Disk almost full disk=sda\x1b[0m\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+// TestConsoleFormatter_RelativeTime verifies the time since start is written instead of the time of day
+func TestConsoleFormatter_RelativeTime(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	logger := log.NewLogger(&buf, log.INFO, &log.ConsoleFormatter{DisableColors: true, DisableCaller: true, RelativeTime: true, Start: start})
+	logger.SetClock(fixedClock{start.Add(1234567 * time.Microsecond)})
+
+	logger.Info("Relative")
+
+	if expected := "+1.235s INFO   Relative\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+// autoFormatOutput logs a message through an "auto" logger and returns what was written
+func autoFormatOutput(t *testing.T, terminal bool) string {
+	t.Helper()
+	defer log.SetIsTerminal(func(*os.File) bool { return terminal })()
+	logger := log.ApplyConfig(log.LoggerConfig{Level: log.INFO, Output: "stdout", Format: "auto"})
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	logger.Info("Auto message")
+	return buf.String()
+}
+
+// TestApplyConfig_AutoTerminal verifies that the auto format picks the console formatter on a terminal
+func TestApplyConfig_AutoTerminal(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("GO_ENV", "")
+
+	if output := autoFormatOutput(t, true); !strings.Contains(output, "\x1b[34mINFO") {
+		t.Errorf("Expected console output on a terminal, got %q", output)
+	}
+}
+
+// TestApplyConfig_AutoNoTerminal verifies that the auto format picks JSON when not on a terminal
+func TestApplyConfig_AutoNoTerminal(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("GO_ENV", "")
+
+	if output := autoFormatOutput(t, false); !json.Valid([]byte(output)) {
+		t.Errorf("Expected JSON output without a terminal, got %q", output)
+	}
+}
+
+// TestApplyConfig_AutoEnvironment verifies that APP_ENV and GO_ENV take precedence over terminal detection
+func TestApplyConfig_AutoEnvironment(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	if output := autoFormatOutput(t, true); !json.Valid([]byte(output)) {
+		t.Errorf("Expected JSON output with APP_ENV=production, got %q", output)
+	}
+
+	t.Setenv("APP_ENV", "")
+	t.Setenv("GO_ENV", "development")
+	if output := autoFormatOutput(t, false); !strings.Contains(output, "\x1b[34mINFO") {
+		t.Errorf("Expected console output with GO_ENV=development, got %q", output)
+	}
+}
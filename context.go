@@ -0,0 +1,30 @@
+package log
+
+import "context"
+
+// ContextExtractor returns the fields to attach to entries logged with a context,
+// or nil if the context carries nothing of interest
+type ContextExtractor func(ctx context.Context) Fields
+
+// AddContextExtractor registers an extractor consulted by WithContext
+func (l *Logger) AddContextExtractor(extractor ContextExtractor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Copy rather than append in place, as WithContext may be iterating the old slice
+	l.extractors = append(l.extractors[:len(l.extractors):len(l.extractors)], extractor)
+}
+
+// WithContext returns an Entry carrying the fields that the logger's context
+// extractors find in ctx, in the order the extractors were added
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	l.mu.RLock()
+	extractors := l.extractors
+	l.mu.RUnlock()
+	entry := &Entry{logger: l}
+	for _, extractor := range extractors {
+		if fields := extractor(ctx); len(fields) > 0 {
+			entry = entry.WithFields(fields)
+		}
+	}
+	return entry
+}
@@ -0,0 +1,37 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+type requestIDKey struct{}
+
+// TestLogger_WithContext verifies that context extractors add their fields to the entry
+func TestLogger_WithContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.AddContextExtractor(func(ctx context.Context) log.Fields {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return log.Fields{"request_id": id}
+		}
+		return nil
+	})
+
+	logger.WithContext(context.WithValue(context.Background(), requestIDKey{}, "abc")).Info("With ID")
+	logger.WithContext(context.Background()).Info("Without ID")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	if !bytes.Contains(lines[0], []byte("request_id=abc")) {
+		t.Errorf("Expected request_id=abc in %q", lines[0])
+	}
+	if bytes.Contains(lines[1], []byte("request_id")) {
+		t.Errorf("Expected no request_id in %q", lines[1])
+	}
+}
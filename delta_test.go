@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// steppingClock is a log.Clock whose time only moves when Advance is called
+type steppingClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *steppingClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// TestLogger_SetShowDelta verifies that each entry carries the time since the previous one
+func TestLogger_SetShowDelta(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	clock := &steppingClock{t: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	logger.SetClock(clock)
+	logger.SetShowDelta(true)
+
+	logger.Info("Loading config")
+	clock.Advance(12 * time.Millisecond)
+	logger.Info("Connecting to the database")
+	clock.Advance(1500 * time.Millisecond)
+	logger.WithDefaults(log.Fields{"component": "http"}).Info("Listening")
+
+	entries := logger.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, expected := range []time.Duration{0, 12 * time.Millisecond, 1500 * time.Millisecond} {
+		if delta := entries[i].Fields["delta"]; delta != expected {
+			t.Errorf("Expected delta %v for %q, got %v", expected, entries[i].Message, delta)
+		}
+	}
+}
+
+// TestLogger_SetShowDeltaConcurrent verifies that concurrent logging never reports a negative delta
+func TestLogger_SetShowDeltaConcurrent(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetShowDelta(true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Info("Concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, entry := range logger.Entries() {
+		if delta, ok := entry.Fields["delta"].(time.Duration); !ok || delta < 0 {
+			t.Fatalf("Expected a non-negative delta, got %v", entry.Fields["delta"])
+		}
+	}
+}
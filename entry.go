@@ -0,0 +1,289 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fields is a set of structured key/value pairs to attach to a log entry
+type Fields map[string]interface{}
+
+// Field is a single structured key/value pair attached to a log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Entry is a log message together with its structured fields. Entries are
+// created with Logger.WithField or Logger.WithFields and are never modified
+// in place: adding fields returns a new Entry.
+type Entry struct {
+	logger     *Logger
+	skipCaller bool        // Caller lookup was skipped because the level is below the logger's CallerMinLevel
+	also       []io.Writer // Extra writers that receive the formatted entry, see ToWriter
+	callerSkip int         // Frames to pass over above the logging call, see WithCallerSkip
+
+	Time     time.Time // When the message was logged
+	Level    LogLevel
+	File     string // Full path of the file that logged the message, empty if caller lookup was skipped
+	Line     int
+	Function string // Fully qualified name of the function that logged the message
+	Message  string
+	Fields   []Field // Structured fields in the order they were added
+}
+
+// EntryFormatter is implemented by formatters that need the time, caller, or structured
+// fields of an entry. When a logger's formatter implements EntryFormatter, FormatEntry
+// is used instead of Format, and the entry's time and caller are captured once by the
+// logger at the point of the log call.
+type EntryFormatter interface {
+	FormatEntry(e Entry) string
+}
+
+// callerReporter is implemented by built-in formatters that can turn off caller information
+type callerReporter interface {
+	reportsCaller() bool
+}
+
+// wantsCaller reports whether a formatter renders caller information
+func wantsCaller(formatter Formatter) bool {
+	if _, ok := formatter.(EntryFormatter); !ok {
+		return false
+	}
+	if reporter, ok := formatter.(callerReporter); ok {
+		return reporter.reportsCaller()
+	}
+	return true
+}
+
+// newEntry builds the entry for a call to a built-in formatter's two-argument Format method
+func newEntry(level LogLevel, message string, caller bool) Entry {
+	e := Entry{Time: time.Now(), Level: level, Message: message}
+	if caller {
+		e.setCaller(1, 0)
+	}
+	return e
+}
+
+// WithField returns an Entry carrying a single structured field
+func (l *Logger) WithField(key string, value interface{}) *Entry {
+	return (&Entry{logger: l}).WithField(key, value)
+}
+
+// WithCallerSkip returns an Entry whose caller is looked up n frames above the call that
+// logs it, see Entry.WithCallerSkip
+func (l *Logger) WithCallerSkip(n int) *Entry {
+	return (&Entry{logger: l}).WithCallerSkip(n)
+}
+
+// WithFields returns an Entry carrying the given structured fields
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return (&Entry{logger: l}).WithFields(fields)
+}
+
+// ToWriter returns an Entry that is also written to w, see Entry.ToWriter
+func (l *Logger) ToWriter(w io.Writer) *Entry {
+	return (&Entry{logger: l}).ToWriter(w)
+}
+
+// WithDefaults returns a copy of the logger that adds the given fields to every entry it logs.
+// Fields attached with WithField or WithFields override defaults with the same key.
+// The copy shares the logger's output and formatter.
+func (l *Logger) WithDefaults(fields Fields) *Logger {
+	child := l.clone()
+	child.defaults = (&Entry{Fields: child.defaults}).WithFields(fields).Fields
+	return child
+}
+
+// loggerNameKey is the field that carries the name given with Named
+const loggerNameKey = "logger"
+
+// Named returns a copy of the logger whose entries carry a "logger" field naming the
+// subsystem that logged them. Names compose with dots, so root.Named("auth").Named("oauth")
+// logs logger=auth.oauth.
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	child := l.WithDefaults(Fields{loggerNameKey: name})
+	child.name = name
+	return child
+}
+
+// withDefaults merges the logger's default fields with the fields of a single call
+func (l *Logger) withDefaults(fields []Field) []Field {
+	if len(l.defaults) == 0 {
+		return fields
+	}
+	merged := make([]Field, len(l.defaults), len(l.defaults)+len(fields))
+	copy(merged, l.defaults)
+	for _, field := range fields {
+		merged = setField(merged, field)
+	}
+	return merged
+}
+
+// WithField returns a copy of the entry with the field added, replacing any field with the same key
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return e.with([]Field{{Key: key, Value: value}})
+}
+
+// ToWriter returns a copy of the entry that, when logged, also writes its formatted text
+// to w, for routing a single message somewhere else without reconfiguring the logger.
+// Errors writing to w are ignored.
+func (e *Entry) ToWriter(w io.Writer) *Entry {
+	also := make([]io.Writer, len(e.also), len(e.also)+1)
+	copy(also, e.also)
+	return &Entry{logger: e.logger, Fields: e.Fields, also: append(also, w), callerSkip: e.callerSkip}
+}
+
+// WithCallerSkip returns a copy of the entry whose caller is reported n frames above the
+// function that logs it. A helper that logs on behalf of its callers uses 1 so that
+// entries show the line calling the helper, and each helper can use its own depth.
+func (e *Entry) WithCallerSkip(n int) *Entry {
+	if n < 0 {
+		n = 0
+	}
+	return &Entry{logger: e.logger, Fields: e.Fields, also: e.also, callerSkip: n}
+}
+
+// WithFields returns a copy of the entry with the fields added, replacing any fields with the same keys.
+// Fields from the map are added in key order so the output is stable.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	added := make([]Field, 0, len(keys))
+	for _, key := range keys {
+		added = append(added, Field{Key: key, Value: fields[key]})
+	}
+	return e.with(added)
+}
+
+// fieldErrorKey is the field that reports values which had to be replaced because they cannot be encoded
+const fieldErrorKey = "_field_error"
+
+// with returns a copy of the entry with the given fields merged into a new field slice.
+// Values that cannot be encoded as JSON are replaced by their %v form and reported in
+// the _field_error field, so one bad value does not lose the whole entry.
+func (e *Entry) with(added []Field) *Entry {
+	return e.withProblems(added, nil)
+}
+
+// withProblems is with, additionally reporting problems found by the caller in _field_error
+func (e *Entry) withProblems(added []Field, problems []string) *Entry {
+	fields := make([]Field, len(e.Fields), len(e.Fields)+len(added))
+	copy(fields, e.Fields)
+	for _, field := range added {
+		if err := checkFieldValue(field.Value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", field.Key, err))
+			field.Value = fmt.Sprintf("%v", field.Value)
+		}
+		fields = setField(fields, field)
+	}
+	if len(problems) > 0 {
+		if previous, ok := lookupField(fields, fieldErrorKey); ok {
+			problems = append([]string{fmt.Sprint(previous)}, problems...)
+		}
+		fields = setField(fields, Field{Key: fieldErrorKey, Value: strings.Join(problems, "; ")})
+	}
+	return &Entry{logger: e.logger, Fields: fields, also: e.also, callerSkip: e.callerSkip}
+}
+
+// checkFieldValue reports whether a field value can be encoded as JSON.
+// Common scalar types are accepted without attempting to encode them.
+func checkFieldValue(value interface{}) error {
+	switch v := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, time.Duration, time.Time:
+		return nil
+	case float64:
+		return checkFloat(v)
+	case float32:
+		return checkFloat(float64(v))
+	}
+	_, err := json.Marshal(value)
+	return err
+}
+
+// checkFloat rejects the float values JSON cannot represent
+func checkFloat(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("unsupported value: %v", f)
+	}
+	return nil
+}
+
+// setField replaces the value of an existing key or appends a new field
+func setField(fields []Field, field Field) []Field {
+	for i := range fields {
+		if fields[i].Key == field.Key {
+			fields[i].Value = field.Value
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// Field returns the value of the field with the given key and whether it was present
+func (e Entry) Field(key string) (interface{}, bool) {
+	return lookupField(e.Fields, key)
+}
+
+// lookupField returns the value of the field with the given key and whether it was present
+func lookupField(fields []Field, key string) (interface{}, bool) {
+	for _, field := range fields {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Log logs a message at the given level with the entry's fields
+func (e *Entry) Log(level LogLevel, v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, level, e.Fields, v...)
+}
+
+// Debug logs a debug message with the entry's fields
+func (e *Entry) Debug(v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, DEBUG, e.Fields, v...)
+}
+
+// Info logs an info message with the entry's fields
+func (e *Entry) Info(v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, INFO, e.Fields, v...)
+}
+
+// Notice logs a notice message with the entry's fields
+func (e *Entry) Notice(v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, NOTICE, e.Fields, v...)
+}
+
+// Warn logs a warning message with the entry's fields
+func (e *Entry) Warn(v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, WARN, e.Fields, v...)
+}
+
+// Error logs an error message with the entry's fields
+func (e *Entry) Error(v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, ERROR, e.Fields, v...)
+}
+
+// Fatal logs a fatal message with the entry's fields and exits the application
+func (e *Entry) Fatal(v ...interface{}) {
+	e.logger.logTo(e.also, e.callerSkip, FATAL, e.Fields, v...)
+}
+
+// FatalWithCode logs a fatal message with the entry's fields and exits the application with code
+func (e *Entry) FatalWithCode(code int, v ...interface{}) {
+	e.logger.withFatalExitCode(code).logTo(e.also, e.callerSkip, FATAL, e.Fields, v...)
+}
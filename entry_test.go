@@ -0,0 +1,324 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestEntry_WithFields verifies that structured fields are written by the JSON formatter
+func TestEntry_WithFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.WithFields(log.Fields{"user": "alice", "attempt": 2}).WithField("ok", true).Info("Login")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["user"] != "alice" || entry["attempt"] != float64(2) || entry["ok"] != true {
+		t.Errorf("Expected user, attempt and ok fields in output, got %v", entry)
+	}
+	if entry["message"] != "Login" {
+		t.Errorf("Expected message 'Login', got %v", entry["message"])
+	}
+}
+
+// TestDefaultFormatter_Fields verifies that the text formatter renders fields as a key=value suffix
+func TestDefaultFormatter_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.WithField("user", "alice").WithField("action", "log in").Info("Login")
+
+	expected := `[INFO] Login user=alice action="log in"` + "\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Errorf("Expected output ending in '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// TestEntry_FieldClash verifies that a field named like a standard key does not overwrite it
+func TestEntry_FieldClash(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.WithField("level", "custom").Info("Clash")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["level"] != "INFO" || entry["fields.level"] != "custom" {
+		t.Errorf("Expected level 'INFO' and fields.level 'custom', got %v", entry)
+	}
+}
+
+// TestJSONFormatter_FieldAllowlist verifies that fields missing from the allowlist are omitted
+func TestJSONFormatter_FieldAllowlist(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{FieldAllowlist: []string{"request_id"}})
+
+	logger.WithFields(log.Fields{"request_id": "r-1", "email": "a@example.com", "card": "4111"}).Info("Payment")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["request_id"] != "r-1" || entry["message"] != "Payment" || entry["level"] != "INFO" {
+		t.Errorf("Expected the allowlisted field and standard fields, got %v", entry)
+	}
+	if _, ok := entry["email"]; ok {
+		t.Errorf("Expected email to be omitted, got %v", entry)
+	}
+	if _, ok := entry["card"]; ok {
+		t.Errorf("Expected card to be omitted, got %v", entry)
+	}
+}
+
+// TestEntry_FatalFields verifies that the fatal entry's fields are written and flushed before exiting
+func TestEntry_FatalFields(t *testing.T) {
+	writer := &flushWriter{}
+	logger := log.NewLogger(writer, log.INFO, &log.JSONFormatter{})
+	exitCode, flushedAtExit := -1, 0
+	logger.SetExitFunc(func(code int) {
+		exitCode, flushedAtExit = code, writer.flushed
+	})
+
+	logger.WithError(errors.New("database unreachable")).WithField("reason", "startup").Fatal("Giving up")
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+	if flushedAtExit != 1 {
+		t.Errorf("Expected the output to be flushed before exiting, got %d flushes", flushedAtExit)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(writer.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["level"] != "FATAL" || entry["reason"] != "startup" || entry["error"] != "database unreachable" {
+		t.Errorf("Expected the fatal entry with reason and error fields, got %v", entry)
+	}
+}
+
+// TestLogger_FatalExitCode verifies the exit codes set with SetFatalExitCode and FatalWithCode
+func TestLogger_FatalExitCode(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+	var codes []int
+	logger.SetExitFunc(func(code int) {
+		codes = append(codes, code)
+	})
+
+	logger.SetFatalExitCode(3)
+	logger.Fatal("Configured code")
+	logger.FatalWithCode(4, "Explicit code")
+	logger.WithField("dependency", "db").FatalWithCode(5, "Explicit code on an entry")
+	logger.Fatal("Configured code again")
+
+	expected := []int{3, 4, 5, 3}
+	if len(codes) != len(expected) {
+		t.Fatalf("Expected exit codes %v, got %v", expected, codes)
+	}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Errorf("Expected exit codes %v, got %v", expected, codes)
+			break
+		}
+	}
+}
+
+// TestEntry_LevelFiltering verifies that entries respect the logger level
+func TestEntry_LevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.WARN, &log.JSONFormatter{})
+
+	logger.WithField("key", "value").Info("Filtered")
+
+	if buf.String() != "" {
+		t.Errorf("Expected no output for Info entry when level is WARN, got %v", buf.String())
+	}
+}
+
+// TestEntry_Field verifies field lookup and that a repeated key replaces the earlier value
+func TestEntry_Field(t *testing.T) {
+	logger := log.NewLogger(&bytes.Buffer{}, log.INFO, &log.DefaultFormatter{})
+
+	entry := logger.WithField("a", 1).WithField("b", 2).WithField("a", 3)
+
+	if value, ok := entry.Field("a"); !ok || value != 3 {
+		t.Errorf("Expected field a=3, got %v (present: %v)", value, ok)
+	}
+	if len(entry.Fields) != 2 || entry.Fields[0].Key != "a" || entry.Fields[1].Key != "b" {
+		t.Errorf("Expected fields [a b] in insertion order, got %v", entry.Fields)
+	}
+	if _, ok := entry.Field("missing"); ok {
+		t.Errorf("Expected missing field to be absent")
+	}
+}
+
+// callerFormatter is a test EntryFormatter that renders the captured caller and time
+type callerFormatter struct{}
+
+func (f *callerFormatter) Format(level log.LogLevel, message string) string {
+	return message
+}
+
+func (f *callerFormatter) FormatEntry(e log.Entry) string {
+	return fmt.Sprintf("%s:%d %s %d %s", filepath.Base(e.File), e.Line, e.Level, e.Time.Year(), e.Message)
+}
+
+// TestEntryFormatter verifies that an EntryFormatter receives the caller and time captured by the logger
+func TestEntryFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	logger := log.NewLogger(&buf, log.INFO, &callerFormatter{})
+	logger.SetClock(fixedClock{clock})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("Entry message")
+
+	expected := fmt.Sprintf("entry_test.go:%d INFO 2024 Entry message\n", line+1)
+	if buf.String() != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// TestEntry_Caller verifies that Entry methods report the test file as the caller
+func TestEntry_Caller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.WithField("key", "value").Warn("Entry message")
+
+	expected := fmt.Sprintf("entry_test.go:%d", line+1)
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected '%v' in output, got '%v'", expected, buf.String())
+	}
+}
+
+// TestEntry_WithFieldsUnencodable verifies that a value JSON cannot encode is replaced rather than losing the entry
+func TestEntry_WithFieldsUnencodable(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.WithFields(log.Fields{"ch": make(chan int), "user": "alice"}).Info("Bad field")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v: %v", err, buf.String())
+	}
+	if entry["message"] != "Bad field" || entry["user"] != "alice" {
+		t.Errorf("Expected message and user field to be kept, got %v", entry)
+	}
+	if ch, ok := entry["ch"].(string); !ok || !strings.HasPrefix(ch, "0x") {
+		t.Errorf("Expected channel to be replaced by its %%v form, got %v", entry["ch"])
+	}
+	if fieldError, ok := entry["_field_error"].(string); !ok || !strings.Contains(fieldError, "ch:") {
+		t.Errorf("Expected a _field_error marker naming the field, got %v", entry["_field_error"])
+	}
+}
+
+// TestLogger_WithDefaults verifies that default fields appear on every entry and per-call fields override them
+func TestLogger_WithDefaults(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	service := logger.WithDefaults(log.Fields{"service": "checkout", "version": "1.2.3"})
+
+	service.Info("Plain message")
+	service.WithField("version", "2.0.0").Info("Override message")
+	logger.Info("Parent message")
+
+	entries := logger.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Fields["service"] != "checkout" || entries[0].Fields["version"] != "1.2.3" {
+		t.Errorf("Expected default fields on plain entry, got %v", entries[0].Fields)
+	}
+	if entries[1].Fields["service"] != "checkout" || entries[1].Fields["version"] != "2.0.0" {
+		t.Errorf("Expected per-call field to override default, got %v", entries[1].Fields)
+	}
+	if len(entries[2].Fields) != 0 {
+		t.Errorf("Expected parent logger to have no default fields, got %v", entries[2].Fields)
+	}
+}
+
+// TestEntry_ToWriter verifies that a single entry is also written to the extra writer
+func TestEntry_ToWriter(t *testing.T) {
+	var buf, extra bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.WithField("disk", "sda").ToWriter(&extra).Error("Disk failing")
+	logger.Info("Routine message")
+
+	if !strings.Contains(buf.String(), "Disk failing disk=sda") || !strings.Contains(buf.String(), "Routine message") {
+		t.Errorf("Expected both messages in the logger's output, got %q", buf.String())
+	}
+	if !strings.HasSuffix(extra.String(), "[ERROR] Disk failing disk=sda\n") {
+		t.Errorf("Expected the error message in the extra writer, got %q", extra.String())
+	}
+	if strings.Contains(extra.String(), "Routine message") {
+		t.Errorf("Expected only the routed message in the extra writer, got %q", extra.String())
+	}
+}
+
+// TestLogger_Named verifies that nested names compose into a dotted logger field
+func TestLogger_Named(t *testing.T) {
+	var buf bytes.Buffer
+	root := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	auth := root.Named("auth")
+
+	auth.Named("oauth").Info("Token refreshed")
+	auth.Info("Login")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "Token refreshed logger=auth.oauth") {
+		t.Errorf("Expected logger=auth.oauth, got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "Login logger=auth") {
+		t.Errorf("Expected logger=auth on the parent, got %q", lines[1])
+	}
+}
+
+// TestEntry_ConcurrentExtension verifies that goroutines extending a shared entry do not see each other's fields
+func TestEntry_ConcurrentExtension(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	base := logger.WithField("a", 1)
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"b", "c"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				base.WithField(key, i).WithField("a", key).Info(key)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	if len(base.Fields) != 1 || base.Fields[0] != (log.Field{Key: "a", Value: 1}) {
+		t.Errorf("Expected the base entry to keep only a=1, got %v", base.Fields)
+	}
+	for _, entry := range logger.Entries() {
+		other := map[string]string{"b": "c", "c": "b"}[entry.Message]
+		if _, ok := entry.Fields[other]; ok || entry.Fields["a"] != entry.Message || len(entry.Fields) != 2 {
+			t.Errorf("Expected only a=%s and %s on the entry, got %v", entry.Message, entry.Message, entry.Fields)
+		}
+	}
+}
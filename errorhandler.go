@@ -0,0 +1,51 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// writeErrorInterval is how often the default error handler reports errors on stderr
+const writeErrorInterval = time.Second
+
+// SetErrorHandler sets the function called with the error whenever the output fails to
+// accept a message, for example when the disk is full or a pipe is broken, so that the
+// application can react by switching to another output. Failing hooks and the switch to
+// a fallback output set with SetFallbackOutput are reported to it as well. The handler is
+// called from the logging goroutine or a HookPool worker and must not log through the same
+// logger. A nil handler restores the default, which reports errors on stderr at most once
+// per second.
+func (l *Logger) SetErrorHandler(handler func(err error)) {
+	if handler == nil {
+		handler = newStderrErrorHandler(l.now)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errorHandler = handler
+}
+
+// newStderrErrorHandler returns an error handler that writes the first error of each
+// interval to stderr, with the number of errors suppressed since the last report. The
+// interval is measured with now, the logger's clock.
+func newStderrErrorHandler(now func() time.Time) func(err error) {
+	var mu sync.Mutex
+	var last time.Time
+	var suppressed int
+	return func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		t := now()
+		if !last.IsZero() && t.Sub(last) < writeErrorInterval {
+			suppressed++
+			return
+		}
+		if suppressed > 0 {
+			fmt.Fprintf(os.Stderr, "Logger error: %v (%d more errors suppressed)\n", err, suppressed)
+		} else {
+			fmt.Fprintf(os.Stderr, "Logger error: %v\n", err)
+		}
+		last, suppressed = t, 0
+	}
+}
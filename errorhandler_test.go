@@ -0,0 +1,42 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_SetErrorHandler verifies that write errors are passed to the error handler
+func TestLogger_SetErrorHandler(t *testing.T) {
+	logger := log.NewLogger(failingWriter{}, log.INFO, &log.DefaultFormatter{})
+	var errs []error
+	logger.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	logger.Info("Lost")
+	logger.WithField("child", true).Info("Also lost")
+
+	if len(errs) != 2 {
+		t.Fatalf("Expected the handler to be called twice, got %d calls", len(errs))
+	}
+	if errs[0].Error() != "disk full" {
+		t.Errorf("Expected the write error to be passed to the handler, got %v", errs[0])
+	}
+}
+
+// TestLogger_SetErrorHandlerNotCalled verifies that successful writes don't call the error handler
+func TestLogger_SetErrorHandlerNotCalled(t *testing.T) {
+	logger := log.NewLogger(&bytes.Buffer{}, log.INFO, &log.DefaultFormatter{})
+	called := false
+	logger.SetErrorHandler(func(err error) {
+		called = true
+	})
+
+	logger.Info("Written")
+
+	if called {
+		t.Errorf("Expected the error handler not to be called for a successful write")
+	}
+}
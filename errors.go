@@ -0,0 +1,80 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// maxErrorChainDepth limits how many wrapped errors are recorded, guarding against cyclic Unwrap
+const maxErrorChainDepth = 16
+
+// ErrorLink describes one error in a chain of wrapped errors
+type ErrorLink struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// WithError returns an Entry carrying err; see Entry.WithError
+func (l *Logger) WithError(err error) *Entry {
+	return (&Entry{logger: l}).WithError(err)
+}
+
+// WithError returns a copy of the entry with err's message in the "error" field.
+// An error that implements json.Marshaler is kept as is instead, so that JSONFormatter
+// writes its JSON form while text formatters still write its message.
+// If err wraps other errors, the "error_chain" field lists err and every error it wraps,
+// depth first, following both Unwrap() error and the Unwrap() []error of errors.Join.
+// A nil err leaves the entry unchanged.
+func (e *Entry) WithError(err error) *Entry {
+	if err == nil {
+		return e.with(nil)
+	}
+	var value interface{} = err.Error()
+	if _, ok := err.(json.Marshaler); ok {
+		value = err
+	}
+	added := []Field{{Key: "error", Value: value}}
+	if chain := errorChain(err); len(chain) > 1 {
+		added = append(added, Field{Key: "error_chain", Value: chain})
+	}
+	return e.with(added)
+}
+
+// Errore logs msg at ERROR with err attached and returns err, so that a function can
+// end with return logger.Errore(err, "save failed"). A nil err is returned without logging.
+func (l *Logger) Errore(err error, msg string) error {
+	return (&Entry{logger: l}).Errore(err, msg)
+}
+
+// Errore logs msg at ERROR with the entry's fields and err attached, and returns err.
+// A nil err is returned without logging.
+func (e *Entry) Errore(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e.WithError(err).Error(msg)
+	return err
+}
+
+// errorChain walks the tree of errors wrapped by err depth first, stopping after
+// maxErrorChainDepth errors
+func errorChain(err error) []ErrorLink {
+	var chain []ErrorLink
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil || len(chain) >= maxErrorChainDepth {
+			return
+		}
+		chain = append(chain, ErrorLink{Message: err.Error(), Type: fmt.Sprintf("%T", err)})
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, wrapped := range joined.Unwrap() {
+				walk(wrapped)
+			}
+			return
+		}
+		walk(errors.Unwrap(err))
+	}
+	walk(err)
+	return chain
+}
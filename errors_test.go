@@ -0,0 +1,169 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestEntry_WithErrorChain verifies that wrapped errors are listed in the error_chain field
+func TestEntry_WithErrorChain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	root := errors.New("connection refused")
+	err := fmt.Errorf("load config: %w", fmt.Errorf("fetch: %w", root))
+	logger.WithError(err).Error("Startup failed")
+
+	var entry struct {
+		Error      string          `json:"error"`
+		ErrorChain []log.ErrorLink `json:"error_chain"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry.Error != "load config: fetch: connection refused" {
+		t.Errorf("Expected the top-level error message, got %q", entry.Error)
+	}
+	expected := []log.ErrorLink{
+		{Message: "load config: fetch: connection refused", Type: "*fmt.wrapError"},
+		{Message: "fetch: connection refused", Type: "*fmt.wrapError"},
+		{Message: "connection refused", Type: "*errors.errorString"},
+	}
+	if len(entry.ErrorChain) != len(expected) {
+		t.Fatalf("Expected %d chain links, got %v", len(expected), entry.ErrorChain)
+	}
+	for i, link := range expected {
+		if entry.ErrorChain[i] != link {
+			t.Errorf("Expected chain link %d to be %v, got %v", i, link, entry.ErrorChain[i])
+		}
+	}
+}
+
+// TestEntry_WithErrorJoined verifies that every error joined with errors.Join is listed in the error_chain field
+func TestEntry_WithErrorJoined(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	disk := errors.New("disk full")
+	err := errors.Join(fmt.Errorf("write: %w", disk), errors.New("close failed"))
+	logger.WithError(err).Error("Save failed")
+
+	var entry struct {
+		ErrorChain []log.ErrorLink `json:"error_chain"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	expected := []log.ErrorLink{
+		{Message: "write: disk full\nclose failed", Type: "*errors.joinError"},
+		{Message: "write: disk full", Type: "*fmt.wrapError"},
+		{Message: "disk full", Type: "*errors.errorString"},
+		{Message: "close failed", Type: "*errors.errorString"},
+	}
+	if len(entry.ErrorChain) != len(expected) {
+		t.Fatalf("Expected %d chain links, got %v", len(expected), entry.ErrorChain)
+	}
+	for i, link := range expected {
+		if entry.ErrorChain[i] != link {
+			t.Errorf("Expected chain link %d to be %v, got %v", i, link, entry.ErrorChain[i])
+		}
+	}
+}
+
+// cyclicError unwraps to itself
+type cyclicError struct{}
+
+func (e *cyclicError) Error() string { return "cycle" }
+func (e *cyclicError) Unwrap() error { return e }
+
+// TestEntry_WithErrorCycle verifies that a cyclic chain is cut off instead of looping forever
+func TestEntry_WithErrorCycle(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.WithError(&cyclicError{}).Error("Cycle")
+
+	var entry struct {
+		ErrorChain []log.ErrorLink `json:"error_chain"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if len(entry.ErrorChain) != 16 {
+		t.Errorf("Expected the chain to stop at 16 links, got %d", len(entry.ErrorChain))
+	}
+}
+
+// TestLogger_Errore verifies that the error is logged and returned unchanged
+func TestLogger_Errore(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{DisableCaller: true})
+	err := errors.New("disk full")
+
+	if returned := logger.WithField("path", "/tmp/a").Errore(err, "Save failed"); returned != err {
+		t.Errorf("Expected the same error to be returned, got %v", returned)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["level"] != "ERROR" || entry["message"] != "Save failed" || entry["error"] != "disk full" || entry["path"] != "/tmp/a" {
+		t.Errorf("Expected an ERROR entry with the error and path fields, got %v", entry)
+	}
+}
+
+// TestLogger_ErroreNil verifies that a nil error is returned without logging
+func TestLogger_ErroreNil(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	if returned := logger.Errore(nil, "Save failed"); returned != nil {
+		t.Errorf("Expected nil, got %v", returned)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for a nil error, got %q", buf.String())
+	}
+}
+
+// domainError is an error with its own JSON representation
+type domainError struct {
+	Code    string
+	Retry   bool
+	message string
+}
+
+func (e *domainError) Error() string {
+	return e.message
+}
+
+func (e *domainError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"code": e.Code, "retry": e.Retry, "message": e.message})
+}
+
+// TestEntry_WithErrorMarshaler verifies that an error implementing json.Marshaler is nested as an object in JSON and its message in text
+func TestEntry_WithErrorMarshaler(t *testing.T) {
+	err := &domainError{Code: "E_QUOTA", Retry: true, message: "quota exceeded"}
+	var jsonOut, text bytes.Buffer
+	log.NewLogger(&jsonOut, log.INFO, &log.JSONFormatter{DisableCaller: true}).WithError(err).Error("Upload failed")
+	log.NewLogger(&text, log.INFO, &log.DefaultFormatter{DisableCaller: true}).WithError(err).Error("Upload failed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(jsonOut.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	expected := map[string]interface{}{"code": "E_QUOTA", "retry": true, "message": "quota exceeded"}
+	if !reflect.DeepEqual(entry["error"], expected) {
+		t.Errorf("Expected error %v, got %v", expected, entry["error"])
+	}
+	if !strings.HasSuffix(text.String(), `Upload failed error="quota exceeded"`+"\n") {
+		t.Errorf("Expected the error message in text, got %q", text.String())
+	}
+}
@@ -147,6 +147,8 @@ func logLevelToString(level log.LogLevel) string {
 		return "DEBUG"
 	case log.INFO:
 		return "INFO"
+	case log.NOTICE:
+		return "NOTICE"
 	case log.WARN:
 		return "WARN"
 	case log.ERROR:
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// CallerPC returns the program counter of its caller's call site
+func CallerPC() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	return pcs[0]
+}
+
+// CachedFrames exposes the cached caller frame lookup for benchmarks
+var CachedFrames = cachedFrames
+
+// ResolveFrames exposes the uncached caller frame lookup for benchmarks
+var ResolveFrames = resolveFrames
+
+// SetIsTerminal replaces the terminal detection used by the auto format and returns a function that restores it
+func SetIsTerminal(f func(*os.File) bool) (restore func()) {
+	previous := isTerminal
+	isTerminal = f
+	return func() {
+		isTerminal = previous
+	}
+}
+
+// SetSignalFuncs replaces signal registration and re-raising for InstallSignalHandler and returns a function that restores them
+func SetSignalFuncs(notify func(chan<- os.Signal, ...os.Signal), stop func(chan<- os.Signal), raise func(os.Signal) error) (restore func()) {
+	previousNotify, previousStop, previousRaise := signalNotify, signalStop, raiseSignal
+	signalNotify, signalStop, raiseSignal = notify, stop, raise
+	return func() {
+		signalNotify, signalStop, raiseSignal = previousNotify, previousStop, previousRaise
+	}
+}
+
+// SetSignalShutdownTimeout replaces how long InstallSignalHandler waits for Shutdown and returns a function that restores it
+func SetSignalShutdownTimeout(timeout time.Duration) (restore func()) {
+	previous := signalShutdownTimeout
+	signalShutdownTimeout = timeout
+	return func() {
+		signalShutdownTimeout = previous
+	}
+}
+
+// SetMainPackage replaces the main module and main package paths used by CallerModulePath and returns a function that restores them
+func SetMainPackage(module, pkg string) (restore func()) {
+	previousModule, previousPackage := mainModule, mainPackage
+	mainModule, mainPackage = module, pkg
+	return func() {
+		mainModule, mainPackage = previousModule, previousPackage
+	}
+}
+
+// SetCloser makes the logger own closer, as if it had opened it
+func (l *Logger) SetCloser(closer io.Closer) {
+	l.output.closer = closer
+}
+
+// SetReadBuildInfo replaces the source of the binary's build information and returns a function that restores it
+func SetReadBuildInfo(f func() (*debug.BuildInfo, bool)) (restore func()) {
+	previous := readBuildInfo
+	readBuildInfo = f
+	return func() {
+		readBuildInfo = previous
+	}
+}
+
+// DefaultMaxLineBytes exposes the longest unterminated line a Writer buffers without a SetMaxMessageBytes limit
+const DefaultMaxLineBytes = defaultMaxLineBytes
+
+// MaxQuietMessages exposes the number of distinct messages SetQuietAfter counts at once
+const MaxQuietMessages = maxQuietMessages
+
+// QuietMessages returns the number of distinct messages the logger's quiet filter is counting
+func (l *Logger) QuietMessages() int {
+	l.mu.RLock()
+	quiet := l.quiet
+	l.mu.RUnlock()
+	quiet.mu.Lock()
+	defer quiet.mu.Unlock()
+	return len(quiet.counts)
+}
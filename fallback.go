@@ -0,0 +1,42 @@
+package log
+
+import (
+	"fmt"
+	"io"
+)
+
+// SetFallbackOutput sets a writer the logger switches to once the output has failed
+// threshold writes in a row, so that messages keep being recorded while, for example, a
+// network sink is down. The message whose failure triggers the switch is written to the
+// fallback, and the switch is reported to the error handler set with SetErrorHandler.
+// A nil fallback disables the switch.
+func (l *Logger) SetFallbackOutput(fallback io.Writer, threshold int) {
+	if threshold < 1 {
+		threshold = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fallback = fallback
+	l.fallbackThreshold = threshold
+	l.outputFailures.Store(0)
+}
+
+// failOver counts a failed write of formatted and switches to the fallback output once
+// the threshold is reached
+func (l *Logger) failOver(formatted string) {
+	failures := l.outputFailures.Add(1)
+	l.mu.Lock()
+	if l.fallback == nil || int(failures) < l.fallbackThreshold {
+		l.mu.Unlock()
+		return
+	}
+	fallback, errorHandler := l.fallback, l.errorHandler
+	l.SetOutput(fallback)
+	l.fallback = nil
+	l.outputFailures.Store(0)
+	l.mu.Unlock()
+
+	io.WriteString(fallback, formatted)
+	// Reported without the lock held, as the default handler reads the logger's clock
+	errorHandler(fmt.Errorf("log output failed %d times in a row, switched to the fallback output", failures))
+}
@@ -0,0 +1,38 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_SetFallbackOutput verifies that the logger switches to the fallback output
+// after the output fails the given number of writes in a row
+func TestLogger_SetFallbackOutput(t *testing.T) {
+	var fallback bytes.Buffer
+	logger := log.NewLogger(failingWriter{}, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	var reported []string
+	logger.SetErrorHandler(func(err error) { reported = append(reported, err.Error()) })
+	logger.SetFallbackOutput(&fallback, 3)
+
+	logger.Info("Lost 1")
+	logger.Info("Lost 2")
+	if fallback.Len() != 0 {
+		t.Fatalf("Expected no switch before the threshold, got %q", fallback.String())
+	}
+	logger.Info("Triggers the switch")
+	logger.Info("After the switch")
+
+	output := fallback.String()
+	if strings.Contains(output, "Lost") {
+		t.Errorf("Expected messages before the switch not to be in the fallback, got %q", output)
+	}
+	if !strings.Contains(output, "Triggers the switch") || !strings.Contains(output, "After the switch") {
+		t.Errorf("Expected messages to land in the fallback after the switch, got %q", output)
+	}
+	if len(reported) != 4 || !strings.Contains(reported[3], "switched to the fallback output") {
+		t.Errorf("Expected the 3 write errors and the switch to be reported, got %q", reported)
+	}
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// fdPrefix marks an Output that names an inherited file descriptor, e.g. "fd://3"
+const fdPrefix = "fd://"
+
+// openDescriptor returns a file for an "fd://N" output, checking that N is open for
+// writing. The file writes to a duplicate of N, so that closing it on Shutdown leaves
+// the inherited descriptor, which the logger does not own, open for the rest of the process.
+func openDescriptor(output string) (*os.File, error) {
+	fd, err := strconv.ParseUint(strings.TrimPrefix(output, fdPrefix), 10, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file descriptor %q", output)
+	}
+	if err := fdWritable(uintptr(fd)); err != nil {
+		return nil, fmt.Errorf("file descriptor %d: %w", fd, err)
+	}
+	dup, err := dupDescriptor(uintptr(fd))
+	if err != nil {
+		return nil, fmt.Errorf("file descriptor %d: %w", fd, err)
+	}
+	return os.NewFile(dup, output), nil
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd)
+
+package log
+
+import "errors"
+
+// fdWritable can't check descriptor access on this platform, so every fd is accepted
+func fdWritable(fd uintptr) error {
+	return nil
+}
+
+// dupDescriptor can't duplicate descriptors on this platform. Using fd directly would
+// let Shutdown close a descriptor the logger does not own, so it is rejected instead.
+func dupDescriptor(fd uintptr) (uintptr, error) {
+	return 0, errors.New("file descriptor outputs are not supported on this platform")
+}
@@ -0,0 +1,32 @@
+//go:build linux || darwin || freebsd
+
+package log
+
+import (
+	"errors"
+	"syscall"
+)
+
+// fdWritable reports an error unless fd is open with write access
+func fdWritable(fd uintptr) error {
+	flags, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, syscall.F_GETFL, 0)
+	if errno != 0 {
+		return errno
+	}
+	if flags&syscall.O_ACCMODE == syscall.O_RDONLY {
+		return errors.New("not open for writing")
+	}
+	return nil
+}
+
+// dupDescriptor returns a close-on-exec duplicate of fd
+func dupDescriptor(fd uintptr) (uintptr, error) {
+	syscall.ForkLock.RLock()
+	defer syscall.ForkLock.RUnlock()
+	dup, err := syscall.Dup(int(fd))
+	if err != nil {
+		return 0, err
+	}
+	syscall.CloseOnExec(dup)
+	return uintptr(dup), nil
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FormatterFactory builds a Formatter from the logger configuration
+type FormatterFactory func(config LoggerConfig) Formatter
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[string]FormatterFactory{
+		"text": func(config LoggerConfig) Formatter {
+			return &DefaultFormatter{DisableCaller: !config.EnableCaller, CallerFormat: config.callerFormat(), TimePrecision: config.timePrecision()}
+		},
+		"json": func(config LoggerConfig) Formatter {
+			return &JSONFormatter{DisableCaller: !config.EnableCaller, CallerFormat: config.callerFormat(), TimePrecision: config.timePrecision(), DurationFormat: config.durationFormat()}
+		},
+		"xml": func(config LoggerConfig) Formatter {
+			return &XMLFormatter{DisableCaller: !config.EnableCaller, CallerFormat: config.callerFormat(), TimePrecision: config.timePrecision()}
+		},
+		"keyvalue": func(config LoggerConfig) Formatter {
+			return &KeyValueFormatter{DisableCaller: !config.EnableCaller, CallerFormat: config.callerFormat(), TimePrecision: config.timePrecision()}
+		},
+		"console": func(config LoggerConfig) Formatter {
+			return &ConsoleFormatter{DisableCaller: !config.EnableCaller, CallerFormat: config.callerFormat()}
+		},
+		"access": func(config LoggerConfig) Formatter {
+			return &AccessLogFormatter{}
+		},
+		"custom": func(config LoggerConfig) Formatter {
+			if config.Custom == nil {
+				fmt.Fprintf(os.Stderr, "Error: Custom formatter is nil")
+				return &DefaultFormatter{DisableCaller: !config.EnableCaller, CallerFormat: config.callerFormat(), TimePrecision: config.timePrecision()}
+			}
+			return config.Custom
+		},
+	}
+)
+
+// BinaryFormatter is implemented by formatters whose output is a binary encoding rather
+// than a line of text. The logger never appends a newline to the output of a formatter
+// whose Binary method returns true.
+type BinaryFormatter interface {
+	Binary() bool
+}
+
+// isBinary reports whether formatter produces binary records
+func isBinary(formatter Formatter) bool {
+	binary, ok := formatter.(BinaryFormatter)
+	return ok && binary.Binary()
+}
+
+// RegisterFormatter makes a formatter available to ApplyConfig under the given
+// case-insensitive name. Registering an existing name replaces its factory.
+func RegisterFormatter(name string, factory FormatterFactory) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formatters[strings.ToLower(name)] = factory
+}
+
+// newFormatter builds the formatter registered for config.Format, falling back to text,
+// and returns it with the name it was registered under.
+// The "auto" format resolves to console or json, see autoFormat. An empty format is
+// inferred from the output's file extension, see extensionFormat.
+func newFormatter(config LoggerConfig) (Formatter, string) {
+	name := strings.ToLower(config.Format)
+	switch name {
+	case "auto":
+		name = autoFormat(config)
+	case "":
+		name = extensionFormat(config.Output)
+	}
+	formattersMu.RLock()
+	factory, ok := formatters[name]
+	if !ok {
+		name = "text"
+		factory = formatters[name]
+	}
+	formattersMu.RUnlock()
+	return factory(config), name
+}
+
+// extensionFormat infers the format from the extension of an output path: json for
+// ".json" and ".ndjson", keyvalue for ".logfmt", and text otherwise
+func extensionFormat(output string) string {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".json", ".ndjson":
+		return "json"
+	case ".logfmt":
+		return "keyvalue"
+	}
+	return "text"
+}
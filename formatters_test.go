@@ -0,0 +1,56 @@
+package log_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// prefixFormatter is a test formatter that prefixes every line with a configurable tag
+type prefixFormatter struct {
+	prefix string
+}
+
+func (f *prefixFormatter) Format(level log.LogLevel, message string) string {
+	return fmt.Sprintf("%s %s %s\n", f.prefix, level, message)
+}
+
+// TestRegisterFormatter verifies that ApplyConfig resolves a registered formatter by name
+func TestRegisterFormatter(t *testing.T) {
+	log.RegisterFormatter("prefixed", func(config log.LoggerConfig) log.Formatter {
+		return &prefixFormatter{prefix: "[" + config.Level.String() + " logger]"}
+	})
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:  log.WARN,
+		Output: path,
+		Format: "Prefixed",
+	})
+
+	logger.Warn("Warn message")
+
+	expected := "[WARN logger] WARN Warn message\n"
+	if output := readLogFile(t, path); output != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, output)
+	}
+}
+
+// TestApplyConfig_UnknownFormat verifies that an unregistered format name falls back to text
+func TestApplyConfig_UnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:  log.INFO,
+		Output: path,
+		Format: "does-not-exist",
+	})
+
+	logger.Info("Info message")
+
+	if output := readLogFile(t, path); !strings.Contains(output, "[INFO] Info message") {
+		t.Errorf("Expected text formatted output, got '%v'", output)
+	}
+}
@@ -0,0 +1,23 @@
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine N [running]:" header of its stack trace, or 0 if it cannot be parsed
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
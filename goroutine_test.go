@@ -0,0 +1,34 @@
+package log_test
+
+import (
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestLogger_SetIncludeGoroutineID verifies that entries from different goroutines carry distinct goroutine IDs
+func TestLogger_SetIncludeGoroutineID(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetIncludeGoroutineID(true)
+
+	logger.Info("From the test goroutine")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("From another goroutine")
+	}()
+	<-done
+
+	entries := logger.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	first, second := entries[0].Fields["goroutine"], entries[1].Fields["goroutine"]
+	if first == nil || second == nil || first == uint64(0) || second == uint64(0) {
+		t.Fatalf("Expected goroutine fields on both entries, got %v and %v", first, second)
+	}
+	if first == second {
+		t.Errorf("Expected distinct goroutine IDs, got %v twice", first)
+	}
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// HookPolicy decides what a HookPool does with an entry when its queue is full
+type HookPolicy int
+
+// Hook pool policies
+const (
+	HookDrop  HookPolicy = iota // Skip the entry's hooks, counted by HookPool.Dropped
+	HookBlock                   // Wait until the queue has room
+)
+
+// HookPool runs hooks on a fixed number of background goroutines, so that hooks doing
+// I/O such as sending alerts do not slow down logging. Entries wait in a bounded queue;
+// when it is full the pool's HookPolicy applies. Install it with Logger.SetHookPool.
+type HookPool struct {
+	queue   chan hookCall
+	policy  HookPolicy
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+
+	mu     sync.RWMutex // Guards closed against concurrent submit and Close
+	closed bool
+}
+
+// hookCall is one entry waiting for its hooks to be fired
+type hookCall struct {
+	hooks        []Hook
+	entry        Entry
+	errorHandler func(err error) // Error handler of the logger that submitted the entry
+}
+
+// NewHookPool starts a HookPool with the given number of workers and queue size
+func NewHookPool(workers, queueSize int, policy HookPolicy) *HookPool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &HookPool{queue: make(chan hookCall, queueSize), policy: policy}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.run()
+	}
+	return p
+}
+
+// run fires queued hooks until the queue is closed
+func (p *HookPool) run() {
+	defer p.wg.Done()
+	for call := range p.queue {
+		runHooks(call.hooks, call.entry, call.errorHandler)
+	}
+}
+
+// submit queues the entry's hooks according to the pool's policy
+func (p *HookPool) submit(hooks []Hook, e Entry, errorHandler func(err error)) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.dropped.Add(1)
+		return
+	}
+	call := hookCall{hooks: hooks, entry: e, errorHandler: errorHandler}
+	if p.policy == HookBlock {
+		p.queue <- call
+		return
+	}
+	select {
+	case p.queue <- call:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of entries whose hooks were skipped because the queue was
+// full or the pool was closed
+func (p *HookPool) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Close waits for the queued hooks to run and stops the workers. Entries logged
+// afterwards have their hooks dropped.
+func (p *HookPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+	p.wg.Wait()
+	return nil
+}
@@ -0,0 +1,63 @@
+package log
+
+import "fmt"
+
+// AllLevels returns every level a message can be logged at, in a new slice the caller may modify
+func AllLevels() []LogLevel {
+	return []LogLevel{DEBUG, INFO, NOTICE, WARN, ERROR, FATAL}
+}
+
+// Hook is notified of every entry logged at one of its levels
+type Hook interface {
+	Levels() []LogLevel
+	Fire(e Entry) error
+}
+
+// AddHook registers a hook that is fired for entries at the levels it reports.
+// It is safe to call while other goroutines are logging.
+func (l *Logger) AddHook(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.hooks == nil {
+		l.hooks = make(map[LogLevel][]Hook)
+	}
+	for _, level := range hook.Levels() {
+		// Copy rather than append in place, as fireHooks may be iterating the old slice
+		levelHooks := make([]Hook, len(l.hooks[level]), len(l.hooks[level])+1)
+		copy(levelHooks, l.hooks[level])
+		l.hooks[level] = append(levelHooks, hook)
+	}
+}
+
+// SetHookPool makes the logger fire hooks on pool instead of in the logging call.
+// FATAL entries still fire their hooks before the program exits. Pass nil to fire
+// hooks in the logging call again, which is the default.
+func (l *Logger) SetHookPool(pool *HookPool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hookPool = pool
+}
+
+// fireHooks runs the hooks registered for the entry's level, on the hook pool if one is set
+func (l *Logger) fireHooks(e Entry) {
+	l.mu.RLock()
+	hooks, pool, errorHandler := l.hooks[e.Level], l.hookPool, l.errorHandler
+	l.mu.RUnlock()
+	if len(hooks) == 0 {
+		return
+	}
+	if pool != nil && e.Level != FATAL {
+		pool.submit(hooks, e, errorHandler)
+		return
+	}
+	runHooks(hooks, e, errorHandler)
+}
+
+// runHooks fires each hook with the entry, reporting failures to the logger's error handler
+func runHooks(hooks []Hook, e Entry, errorHandler func(err error)) {
+	for _, hook := range hooks {
+		if err := hook.Fire(e); err != nil {
+			errorHandler(fmt.Errorf("firing log hook: %w", err))
+		}
+	}
+}
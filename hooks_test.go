@@ -0,0 +1,230 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// recordingHook is a test hook that records the entries it is fired with
+type recordingHook struct {
+	levels  []log.LogLevel
+	entries []log.Entry
+	err     error
+}
+
+func (h *recordingHook) Levels() []log.LogLevel {
+	return h.levels
+}
+
+func (h *recordingHook) Fire(e log.Entry) error {
+	h.entries = append(h.entries, e)
+	return h.err
+}
+
+// TestLogger_AddHook verifies that hooks fire only for their levels and receive the entry fields
+func TestLogger_AddHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.DEBUG, &log.DefaultFormatter{})
+	hook := &recordingHook{levels: []log.LogLevel{log.ERROR}}
+	logger.AddHook(hook)
+
+	logger.Info("Info message")
+	logger.WithField("code", 42).Error("Error message")
+
+	if len(hook.entries) != 1 {
+		t.Fatalf("Expected hook to fire once, got %d", len(hook.entries))
+	}
+	if hook.entries[0].Message != "Error message" {
+		t.Errorf("Expected message 'Error message', got '%v'", hook.entries[0].Message)
+	}
+	if value, _ := hook.entries[0].Field("code"); value != 42 {
+		t.Errorf("Expected field code=42, got %v", value)
+	}
+}
+
+// TestLogger_HookError verifies that a failing hook does not prevent the message being
+// written and that its error reaches the error handler
+func TestLogger_HookError(t *testing.T) {
+	var buf bytes.Buffer
+	var reported []error
+	hookErr := errors.New("hook failed")
+	logger := log.NewLogger(&buf, log.DEBUG, &log.DefaultFormatter{})
+	logger.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	logger.AddHook(&recordingHook{levels: log.AllLevels(), err: hookErr})
+
+	logger.Warn("Warn message")
+
+	if !containsLogMessage(buf.String(), "WARN", "Warn message") {
+		t.Errorf("Expected 'WARN - Warn message' in output, got %v", buf.String())
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], hookErr) {
+		t.Errorf("Expected the hook error to be reported once, got %v", reported)
+	}
+}
+
+// TestAllLevels verifies that AllLevels returns a copy the caller cannot use to change the levels
+func TestAllLevels(t *testing.T) {
+	levels := log.AllLevels()
+	levels[0] = log.FATAL
+
+	if first := log.AllLevels()[0]; first != log.DEBUG {
+		t.Errorf("Expected DEBUG to stay the first level, got %v", first)
+	}
+}
+
+// blockingHook is a test hook that signals started and waits for release before recording each entry
+type blockingHook struct {
+	started chan struct{}
+	release chan struct{}
+	fired   chan string
+}
+
+func newBlockingHook() *blockingHook {
+	return &blockingHook{started: make(chan struct{}, 8), release: make(chan struct{}), fired: make(chan string, 8)}
+}
+
+func (h *blockingHook) Levels() []log.LogLevel {
+	return log.AllLevels()
+}
+
+func (h *blockingHook) Fire(e log.Entry) error {
+	h.started <- struct{}{}
+	<-h.release
+	h.fired <- e.Message
+	return nil
+}
+
+// TestLogger_SetHookPool verifies that a slow hook runs in the background and still fires
+func TestLogger_SetHookPool(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+	hook := newBlockingHook()
+	logger.AddHook(hook)
+	pool := log.NewHookPool(1, 8, log.HookDrop)
+	logger.SetHookPool(pool)
+
+	logger.Error("Alert message") // Would block until release if the hook ran inline
+
+	if !containsLogMessage(buf.String(), "ERROR", "Alert message") {
+		t.Errorf("Expected the message to be written before the hook finished, got %q", buf.String())
+	}
+	close(hook.release)
+	select {
+	case message := <-hook.fired:
+		if message != "Alert message" {
+			t.Errorf("Expected the hook to fire with 'Alert message', got %q", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the hook to fire eventually")
+	}
+	pool.Close()
+}
+
+// TestHookPool_ErrorHandler verifies that errors from hooks run on the pool reach the logger's error handler
+func TestHookPool_ErrorHandler(t *testing.T) {
+	reported := make(chan error, 1)
+	hookErr := errors.New("hook failed")
+	logger := log.NewLogger(&bytes.Buffer{}, log.INFO, &log.DefaultFormatter{})
+	logger.SetErrorHandler(func(err error) { reported <- err })
+	logger.AddHook(&recordingHook{levels: log.AllLevels(), err: hookErr})
+	pool := log.NewHookPool(1, 8, log.HookDrop)
+	logger.SetHookPool(pool)
+
+	logger.Error("Alert message")
+	pool.Close()
+
+	select {
+	case err := <-reported:
+		if !errors.Is(err, hookErr) {
+			t.Errorf("Expected the hook error, got %v", err)
+		}
+	default:
+		t.Errorf("Expected the hook error to be reported")
+	}
+}
+
+// TestHookPool_Drop verifies that entries are dropped once the workers are busy and the queue is full
+func TestHookPool_Drop(t *testing.T) {
+	logger := log.NewLogger(&bytes.Buffer{}, log.INFO, &log.DefaultFormatter{})
+	hook := newBlockingHook()
+	logger.AddHook(hook)
+	pool := log.NewHookPool(1, 1, log.HookDrop)
+	logger.SetHookPool(pool)
+
+	logger.Info("Running")
+	<-hook.started
+	logger.Info("Queued")
+	logger.Info("Dropped")
+	close(hook.release)
+	pool.Close()
+
+	if dropped := pool.Dropped(); dropped != 1 {
+		t.Errorf("Expected 1 dropped entry, got %d", dropped)
+	}
+	if fired := len(hook.fired); fired != 2 {
+		t.Errorf("Expected the running and the queued entry to fire, got %d", fired)
+	}
+}
+
+// countingHook is a test hook that counts how often it fires, safe for concurrent use
+type countingHook struct {
+	fired atomic.Int64
+}
+
+func (h *countingHook) Levels() []log.LogLevel {
+	return log.AllLevels()
+}
+
+func (h *countingHook) Fire(e log.Entry) error {
+	h.fired.Add(1)
+	return nil
+}
+
+// TestLogger_AddHookConcurrent verifies that hooks can be added while another goroutine logs; run with -race
+func TestLogger_AddHookConcurrent(t *testing.T) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	const hooks = 20
+	var wg sync.WaitGroup
+	started, done := make(chan struct{}), make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("First message")
+		close(started)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				logger.Info("Concurrent message")
+			}
+		}
+	}()
+	<-started
+	added := make([]*countingHook, hooks)
+	for i := range added {
+		added[i] = &countingHook{}
+		logger.AddHook(added[i])
+	}
+	close(done)
+	wg.Wait()
+
+	before := make([]int64, hooks)
+	for i, hook := range added {
+		before[i] = hook.fired.Load()
+	}
+	logger.Info("Final message")
+	for i, hook := range added {
+		if fired := hook.fired.Load() - before[i]; fired != 1 {
+			t.Errorf("Expected hook %d to fire once for the final message, got %d", i, fired)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// HTTPMiddleware wraps an http.Handler and logs one INFO entry per request with the
+// method, escaped path, protocol, status, bytes written, duration, and remote host as
+// structured fields. The field names match those read by AccessLogFormatter.
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return l.HTTPMiddlewareLevel(INFO, next)
+}
+
+// HTTPMiddlewareLevel is like HTTPMiddleware but logs each request at the given level
+func (l *Logger) HTTPMiddlewareLevel(level LogLevel, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.mu.RLock()
+		clock := l.clock
+		l.mu.RUnlock()
+		start := clock.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		// The escaped path keeps percent-encoded newlines and quotes out of the log line
+		path := r.URL.EscapedPath()
+		fields := []Field{
+			{Key: "method", Value: r.Method},
+			{Key: "path", Value: path},
+			{Key: "proto", Value: r.Proto},
+			{Key: "status", Value: rw.status},
+			{Key: "bytes", Value: rw.bytes},
+			{Key: "duration", Value: clock.Now().Sub(start)},
+			{Key: "remote", Value: remoteHost(r.RemoteAddr)},
+		}
+		l.log(level, fields, r.Method, " ", path)
+	})
+}
+
+// remoteHost strips the port from a request's remote address, returning addresses
+// without a port unchanged
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// responseWriter records the status code and number of bytes written by a handler
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush sends buffered data to the client if the underlying ResponseWriter supports it,
+// so streaming handlers keep working behind the middleware
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, for example to upgrade to WebSocket
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,159 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestLogger_HTTPMiddleware verifies the fields logged for a successful request
+func TestLogger_HTTPMiddleware(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	handler := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/hello", nil))
+
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].Fields
+	if entries[0].Level != log.INFO {
+		t.Errorf("Expected level INFO, got %v", entries[0].Level)
+	}
+	if fields["method"] != "GET" || fields["path"] != "/hello" {
+		t.Errorf("Expected method GET and path /hello, got %v", fields)
+	}
+	if fields["status"] != http.StatusOK || fields["bytes"] != 5 {
+		t.Errorf("Expected status 200 and 5 bytes, got %v", fields)
+	}
+	if fields["remote"] != "192.0.2.1" {
+		t.Errorf("Expected remote 192.0.2.1, got %v", fields["remote"])
+	}
+	if _, ok := fields["duration"].(time.Duration); !ok {
+		t.Errorf("Expected a duration field, got %v", fields["duration"])
+	}
+}
+
+// TestLogger_HTTPMiddlewareClock verifies that the request duration is measured with the logger's clock
+func TestLogger_HTTPMiddlewareClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetClock(clock)
+	handler := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(250 * time.Millisecond)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if duration := entries[0].Fields["duration"]; duration != 250*time.Millisecond {
+		t.Errorf("Expected duration 250ms, got %v", duration)
+	}
+}
+
+// TestLogger_HTTPMiddlewareError verifies the status logged for a failing request and the configured level
+func TestLogger_HTTPMiddlewareError(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	handler := logger.HTTPMiddlewareLevel(log.WARN, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/fail", nil))
+
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Level != log.WARN {
+		t.Errorf("Expected level WARN, got %v", entries[0].Level)
+	}
+	if entries[0].Fields["status"] != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %v", entries[0].Fields["status"])
+	}
+	if entries[0].Fields["method"] != "POST" {
+		t.Errorf("Expected method POST, got %v", entries[0].Fields["method"])
+	}
+}
+
+// TestLogger_HTTPMiddlewareFlusher verifies that streaming handlers can still flush through the middleware
+func TestLogger_HTTPMiddlewareFlusher(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	handler := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatalf("Expected the ResponseWriter to implement http.Flusher")
+		}
+		w.Write([]byte("chunk"))
+		flusher.Flush()
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if !recorder.Flushed {
+		t.Errorf("Expected the underlying recorder to be flushed")
+	}
+}
+
+// TestLogger_HTTPMiddlewareHijacker verifies that handlers can hijack the connection through the middleware
+func TestLogger_HTTPMiddlewareHijacker(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	server := httptest.NewServer(logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Expected the connection to be hijacked, got %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		rw.Flush()
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected a response, got %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hijacked" {
+		t.Errorf("Expected the hijacked response, got %q", body)
+	}
+
+	// A plain recorder can't be hijacked; the middleware reports that rather than panicking
+	handler := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); err != http.ErrNotSupported {
+			t.Errorf("Expected %v, got %v", http.ErrNotSupported, err)
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// TestLogger_HTTPMiddlewareEscapedPath verifies that the path is logged as sent, without decoding percent escapes
+func TestLogger_HTTPMiddlewareEscapedPath(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.AccessLogFormatter{})
+	handler := logger.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a%0A6.6.6.6%20-%20admin%20%22GET%20/secret", nil))
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Errorf("Expected a single line, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"GET /a%0A6.6.6.6%20-%20admin%20%22GET%20/secret HTTP/1.1"`) {
+		t.Errorf("Expected the escaped path, got %q", buf.String())
+	}
+}
@@ -0,0 +1,10 @@
+// Package pkglog logs from a package other than the logger's own, for the tests of
+// per-package levels.
+package pkglog
+
+import log "github.com/pod32g/simple-logger"
+
+// Info logs message at INFO through logger from this package
+func Info(logger *log.Logger, message string) {
+	logger.Info(message)
+}
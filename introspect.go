@@ -0,0 +1,101 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Config returns the logger's current settings as a LoggerConfig, for example to show them
+// on a debug endpoint. Outputs and formatters set through a config are reported by the
+// names used there. Those set directly with SetOutput or SetFormatter are described as
+// closely as possible: "stdout", "stderr" or the file name for outputs, and the format
+// name of a built-in formatter, or "custom" for any other.
+func (l *Logger) Config() LoggerConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.output.mu.RLock()
+	defer l.output.mu.RUnlock()
+
+	config := LoggerConfig{
+		Level:              l.getLevel(),
+		Output:             l.output.name,
+		Format:             l.formatName,
+		EnableCaller:       wantsCaller(l.formatter),
+		CallerMinLevel:     l.callerMinLevel,
+		InitialBufferSize:  l.bufferSize,
+		MaxMessageBytes:    l.maxMessageBytes,
+		MaxFields:          l.maxFields,
+		FlushOnError:       l.flushLevel <= ERROR,
+		Sequence:           l.withSequence,
+		IncludeGoroutineID: l.withGoroutineID,
+		ShowDelta:          l.showDelta,
+		FatalExitCode:      l.fatalExitCode,
+	}
+	if len(l.defaults) > 0 {
+		config.Fields = make(Fields, len(l.defaults))
+		for _, field := range l.defaults {
+			config.Fields[field.Key] = field.Value
+		}
+	}
+	if l.quiet != nil {
+		config.QuietAfter = l.quiet.after
+	}
+	if config.Output == "" {
+		config.Output = describeOutput(l.output.writer)
+	}
+	if config.Format == "" {
+		config.Format = formatterName(l.formatter)
+	}
+	if formatterName(l.formatter) == "custom" {
+		config.Custom = l.formatter
+	}
+	switch f := l.formatter.(type) {
+	case *DefaultFormatter:
+		config.CallerFormat, config.TimePrecision = f.CallerFormat.String(), f.TimePrecision.String()
+	case *JSONFormatter:
+		config.CallerFormat, config.TimePrecision = f.CallerFormat.String(), f.TimePrecision.String()
+		config.DurationFormat = f.DurationFormat.String()
+	case *XMLFormatter:
+		config.CallerFormat, config.TimePrecision = f.CallerFormat.String(), f.TimePrecision.String()
+	case *KeyValueFormatter:
+		config.CallerFormat, config.TimePrecision = f.CallerFormat.String(), f.TimePrecision.String()
+	case *ConsoleFormatter:
+		config.CallerFormat = f.CallerFormat.String()
+	}
+	return config
+}
+
+// describeOutput names an output that was not set through a config
+func describeOutput(output io.Writer) string {
+	switch output {
+	case os.Stdout:
+		return "stdout"
+	case os.Stderr:
+		return "stderr"
+	}
+	if file, ok := output.(*os.File); ok {
+		return file.Name()
+	}
+	return fmt.Sprintf("%T", output)
+}
+
+// formatterName returns the format name of a built-in formatter, or "custom"
+func formatterName(formatter Formatter) string {
+	switch formatter.(type) {
+	case *DefaultFormatter:
+		return "text"
+	case *JSONFormatter:
+		return "json"
+	case *XMLFormatter:
+		return "xml"
+	case *KeyValueFormatter:
+		return "keyvalue"
+	case *ConsoleFormatter:
+		return "console"
+	case *AccessLogFormatter:
+		return "access"
+	default:
+		return "custom"
+	}
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+)
+
+// KeyValueFormatter formats each entry as a single line of key=value pairs, in the
+// style of logfmt:
+//
+//	time=2006-01-02T15:04:05Z07:00 level=INFO caller=main.go:12 msg="Request served" status=200
+//
+// It is a complete example of an EntryFormatter that uses the entry's time, caller
+// and structured fields. Values containing spaces, quotes or "=" are quoted.
+type KeyValueFormatter struct {
+	DisableCaller bool          // Skip the caller lookup and omit the caller pair
+	CallerFormat  CallerFormat  // How the caller's file is rendered, base name by default
+	TimePrecision TimePrecision // Sub-second precision of the timestamp, seconds by default
+}
+
+func (f *KeyValueFormatter) Format(level LogLevel, message string) string {
+	return f.FormatEntry(newEntry(level, message, f.reportsCaller()))
+}
+
+// FormatEntry formats an entry as a line of key=value pairs
+func (f *KeyValueFormatter) FormatEntry(e Entry) string {
+	var b strings.Builder
+	b.WriteString("time=")
+	b.WriteString(e.Time.Format(rfc3339Layout(f.TimePrecision)))
+	b.WriteString(" level=")
+	b.WriteString(e.Level.String())
+	if !f.DisableCaller && !e.skipCaller {
+		b.WriteString(" caller=")
+		b.WriteString(textValue(callerFile(e, f.CallerFormat) + ":" + strconv.Itoa(e.Line)))
+	}
+	b.WriteString(" msg=")
+	b.WriteString(textValue(e.Message))
+	b.WriteString(textFields(e.Fields))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+func (f *KeyValueFormatter) reportsCaller() bool {
+	return !f.DisableCaller
+}
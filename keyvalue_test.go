@@ -0,0 +1,38 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestKeyValueFormatter verifies that fields and the caller are rendered as key=value pairs
+func TestKeyValueFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.KeyValueFormatter{})
+	logger.SetClock(fixedClock{time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.WithFields(log.Fields{"status": 200, "path": "/a b"}).Info("Request served")
+
+	expected := fmt.Sprintf("time=2024-03-01T12:30:00Z level=INFO caller=keyvalue_test.go:%d msg=\"Request served\" path=\"/a b\" status=200\n", line+1)
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+// TestKeyValueFormatter_DisableCaller verifies that the caller pair is omitted when disabled
+func TestKeyValueFormatter_DisableCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.KeyValueFormatter{DisableCaller: true})
+
+	logger.Warn("plain")
+
+	if bytes.Contains(buf.Bytes(), []byte("caller=")) || !bytes.Contains(buf.Bytes(), []byte("level=WARN msg=plain\n")) {
+		t.Errorf("Expected no caller pair and an unquoted message, got %q", buf.String())
+	}
+}
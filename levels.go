@@ -0,0 +1,87 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// LevelScheme selects the numeric codes used to represent levels for log aggregators
+type LevelScheme int
+
+// Numeric level schemes
+const (
+	BunyanLevels  LevelScheme = iota // debug=20, info=30, notice=35, warn=40, error=50, fatal=60
+	SyslogLevels                     // RFC 5424 severities: debug=7, info=6, notice=5, warning=4, error=3, critical=2
+	OrdinalLevels                    // The LogLevel constants themselves: debug=0, info=1, notice=2, warn=3, error=4, fatal=5
+)
+
+// Value returns the numeric code of a level in the scheme
+func (s LevelScheme) Value(level LogLevel) int {
+	switch s {
+	case OrdinalLevels:
+		return int(level)
+	case SyslogLevels:
+		switch level {
+		case DEBUG:
+			return 7
+		case INFO:
+			return 6
+		case NOTICE:
+			return 5
+		case WARN:
+			return 4
+		case ERROR:
+			return 3
+		default:
+			return 2
+		}
+	default:
+		switch level {
+		case DEBUG:
+			return 20
+		case INFO:
+			return 30
+		case NOTICE:
+			return 35
+		case WARN:
+			return 40
+		case ERROR:
+			return 50
+		default:
+			return 60
+		}
+	}
+}
+
+// MarshalText encodes the level as its name, so configs written back out stay readable
+func (l LogLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level name accepted by ParseLevel. A bare number is still
+// accepted for older configs, which stored the LogLevel constant itself.
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	if n, err := strconv.Atoi(string(text)); err == nil {
+		if n < int(DEBUG) || n > int(OFF) {
+			return fmt.Errorf("log level %d out of range", n)
+		}
+		*l = LogLevel(n)
+		return nil
+	}
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// UnmarshalJSON decodes a level from either a quoted name or a number
+func (l *LogLevel) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return l.UnmarshalText([]byte(name))
+	}
+	return l.UnmarshalText(data)
+}
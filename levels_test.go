@@ -0,0 +1,104 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// numericLevel logs a message at level through a JSON formatter and returns its level_num field
+func numericLevel(t *testing.T, formatter *log.JSONFormatter, level log.LogLevel) interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.DEBUG, formatter)
+
+	logger.Log(level, "Numeric level message")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["level"] != level.String() {
+		t.Errorf("Expected level %v alongside the number, got %v", level, entry["level"])
+	}
+	return entry["level_num"]
+}
+
+// TestJSONFormatter_NumericLevelBunyan verifies the Bunyan numeric level codes
+func TestJSONFormatter_NumericLevelBunyan(t *testing.T) {
+	expected := map[log.LogLevel]float64{log.DEBUG: 20, log.INFO: 30, log.WARN: 40, log.ERROR: 50}
+	for level, code := range expected {
+		if got := numericLevel(t, &log.JSONFormatter{NumericLevel: true}, level); got != code {
+			t.Errorf("Expected level_num %v for %v, got %v", code, level, got)
+		}
+	}
+}
+
+// TestJSONFormatter_NumericLevelSyslog verifies the syslog numeric level codes
+func TestJSONFormatter_NumericLevelSyslog(t *testing.T) {
+	formatter := &log.JSONFormatter{NumericLevel: true, LevelScheme: log.SyslogLevels}
+	expected := map[log.LogLevel]float64{log.DEBUG: 7, log.INFO: 6, log.NOTICE: 5, log.WARN: 4, log.ERROR: 3}
+	for level, code := range expected {
+		if got := numericLevel(t, formatter, level); got != code {
+			t.Errorf("Expected level_num %v for %v, got %v", code, level, got)
+		}
+	}
+}
+
+// TestJSONFormatter_NumericLevelDisabled verifies that no numeric level is written by default
+func TestJSONFormatter_NumericLevelDisabled(t *testing.T) {
+	if got := numericLevel(t, &log.JSONFormatter{}, log.INFO); got != nil {
+		t.Errorf("Expected no level_num by default, got %v", got)
+	}
+}
+
+// TestLogLevel_UnmarshalConfig verifies that configs accept level names as well as the older numbers
+func TestLogLevel_UnmarshalConfig(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+		want   log.LogLevel
+	}{
+		{"json", `{"level": "warn"}`, log.WARN},
+		{"json", `{"level": "Critical"}`, log.FATAL},
+		{"json", `{"level": 4}`, log.ERROR},
+		{"yaml", "level: notice", log.NOTICE},
+		{"yaml", "level: off", log.OFF},
+		{"yaml", "level: 0", log.DEBUG},
+	}
+	for _, test := range tests {
+		config, err := log.LoadConfigFromReaderFormat(strings.NewReader(test.input), test.format)
+		if err != nil {
+			t.Errorf("Expected %s to load, got %v", test.input, err)
+			continue
+		}
+		if config.Level != test.want {
+			t.Errorf("Expected level %v for %s, got %v", test.want, test.input, config.Level)
+		}
+	}
+
+	for _, input := range []string{`{"level": "loud"}`, `{"level": 42}`} {
+		if _, err := log.LoadConfigFromReader(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
+
+// TestLogLevel_MarshalText verifies that levels are written out by name
+func TestLogLevel_MarshalText(t *testing.T) {
+	data, err := json.Marshal(log.LoggerConfig{Level: log.NOTICE})
+	if err != nil {
+		t.Fatalf("Expected the config to encode, got %v", err)
+	}
+	if !strings.Contains(string(data), `"level":"NOTICE"`) {
+		t.Errorf("Expected the level name, got %s", data)
+	}
+
+	var config log.LoggerConfig
+	if err := json.Unmarshal(data, &config); err != nil || config.Level != log.NOTICE {
+		t.Errorf("Expected NOTICE to round-trip, got %v (%v)", config.Level, err)
+	}
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"errors"
+	"io"
+)
+
+// LevelWriter is an output that writes every entry to one writer and additionally
+// copies entries at or above a level to a second writer, for example errors to a
+// separate file for quick triage. Both receive the text formatted by the logger.
+type LevelWriter struct {
+	output   io.Writer
+	minLevel LogLevel
+	copy     io.Writer
+}
+
+// NewLevelWriter returns a LevelWriter writing to output and copying entries at or
+// above minLevel to copy
+func NewLevelWriter(output io.Writer, minLevel LogLevel, copy io.Writer) *LevelWriter {
+	return &LevelWriter{output: output, minLevel: minLevel, copy: copy}
+}
+
+// WriteEntry writes formatted to the output, and to the copy if the entry's level is
+// at or above the minimum level. A failing writer does not stop the other; their
+// errors are joined.
+func (w *LevelWriter) WriteEntry(e Entry, formatted string) error {
+	_, err := io.WriteString(w.output, formatted)
+	if e.Level >= w.minLevel {
+		_, copyErr := io.WriteString(w.copy, formatted)
+		err = errors.Join(err, copyErr)
+	}
+	return err
+}
+
+// Write writes p to the output only, as it carries no level
+func (w *LevelWriter) Write(p []byte) (int, error) {
+	return w.output.Write(p)
+}
+
+// Flush flushes both writers if they support it
+func (w *LevelWriter) Flush() error {
+	var errs []error
+	for _, writer := range []io.Writer{w.output, w.copy} {
+		switch writer := writer.(type) {
+		case Flusher:
+			errs = append(errs, writer.Flush())
+		case syncer:
+			errs = append(errs, syncOutput(writer))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync commits both writers to stable storage if they are files
+func (w *LevelWriter) Sync() error {
+	var errs []error
+	for _, writer := range []io.Writer{w.output, w.copy} {
+		if writer, ok := writer.(syncer); ok {
+			errs = append(errs, syncOutput(writer))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closers closes several outputs opened by the logger
+type closers []io.Closer
+
+// Close closes every non-nil closer, joining their errors
+func (c closers) Close() error {
+	var errs []error
+	for _, closer := range c {
+		if closer != nil {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -1,13 +1,22 @@
 package log
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
+	"unicode/utf8"
 )
 
 // LogLevel represents the severity of the log message
@@ -17,9 +26,11 @@ type LogLevel int
 const (
 	DEBUG LogLevel = iota
 	INFO
+	NOTICE
 	WARN
 	ERROR
 	FATAL
+	OFF // Disables all output when used as the logger level
 )
 
 // Formatter defines an interface for formatting log messages
@@ -27,129 +38,1251 @@ type Formatter interface {
 	Format(level LogLevel, message string) string
 }
 
+// Flusher is implemented by outputs that buffer data and can write it out on demand
+type Flusher interface {
+	Flush() error
+}
+
+// syncer is implemented by outputs such as *os.File that can commit their contents to storage
+type syncer interface {
+	Sync() error
+}
+
+// syncOutput calls Sync on output, ignoring the errors returned for pipes, terminals
+// and other descriptors that can't be synced, such as os.Stdout redirected to a pipe
+func syncOutput(output syncer) error {
+	err := output.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+		return nil
+	}
+	return err
+}
+
+// sharedOutput is the output of a logger, shared with the loggers derived from it so
+// that replacing it, for example with Reopen, is seen by all of them
+type sharedOutput struct {
+	mu     sync.RWMutex // Held for reading while writing, so that the output is not swapped mid-write
+	writer io.Writer
+	closer io.Closer // Output opened by the logger itself, closed on Shutdown
+	name   string    // Output as named in the config, reported by Config; empty if set directly
+}
+
+// get returns the current writer
+func (o *sharedOutput) get() io.Writer {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.writer
+}
+
+// replace installs writer, closer and name, returning the closer it replaces
+func (o *sharedOutput) replace(writer io.Writer, closer io.Closer, name string) io.Closer {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	previous := o.closer
+	o.writer, o.closer, o.name = writer, closer, name
+	return previous
+}
+
 // Logger represents a logging instance
 type Logger struct {
-	level     LogLevel
-	output    io.Writer
-	formatter Formatter
+	level      atomic.Int32  // Current LogLevel, read and written atomically
+	mu         sync.RWMutex  // Guards the fields below that are not atomic, so that settings can change while logging
+	output     *sharedOutput // Shared with loggers derived with Named, WithDefaults and For
+	ownsOutput bool          // Close the output's closer on Shutdown; false for derived loggers
+	formatter  Formatter
+	byLevel    map[LogLevel]Formatter // Formatters overriding formatter for specific levels
+	formatName string                 // Format as named in the config, reported by Config; empty if set directly
+	hooks      map[LogLevel][]Hook
+	hookPool   *HookPool // Runs hooks in the background, nil to run them in the logging call
+	extractors []ContextExtractor
+	defaults   []Field // Fields added to every entry, overridden by per-call fields
+	name       string  // Dotted name set by Named, empty for an unnamed logger
+
+	packageLevels atomic.Pointer[[]packageLevel] // Levels set with SetPackageLevel, nil if none
+	stats         *logStats
+	sequence      *atomic.Uint64 // Last sequence number handed out, shared with loggers derived with WithDefaults
+	lastLogged    *atomic.Int64  // Time of the last entry in Unix nanoseconds for SetShowDelta, shared like sequence
+	onceKeys      *sync.Map      // Keys already logged by the *Once methods, shared like sequence
+
+	ensureNewline   bool     // Terminate formatted output with a newline if the formatter did not
+	syncLevel       LogLevel // Sync file outputs after messages at or above this level, OFF to disable
+	flushLevel      LogLevel // Flush buffered outputs after messages at or above this level, OFF to disable
+	stacktraceLevel LogLevel // Attach a stack trace to messages at or above this level, OFF to disable
+	bufferSize      int      // Initial capacity of the buffer used to assemble messages
+	maxMessageBytes int      // Truncate messages longer than this many bytes, 0 for no limit
+	maxFields       int      // Drop structured fields beyond this many, 0 for no limit
+	callerMinLevel  LogLevel // Look up the caller only for messages at or above this level
+	exitFunc        func(code int)
+	fatalExitCode   int
+	errorHandler    func(err error) // Called when the output fails to accept a message
+	withSequence    bool            // Add an increasing "seq" field to every entry
+	clock           Clock
+	quiet           *quietFilter
+	showDelta       bool // Add a "delta" field with the time since the previous entry
+	withGoroutineID bool // Add a "goroutine" field with the logging goroutine's ID
+
+	fallback          io.Writer // Output to switch to after fallbackThreshold failed writes in a row
+	fallbackThreshold int
+	outputFailures    atomic.Int32 // Failed writes in a row
+
+	reraiseSignal atomic.Bool // Re-send signals handled by InstallSignalHandler after shutting down
+	shutdownOnce  sync.Once
 }
 
 // NewLogger creates a new Logger instance
 func NewLogger(output io.Writer, level LogLevel, formatter Formatter) *Logger {
-	return &Logger{
-		level:     level,
-		output:    output,
-		formatter: formatter,
+	logger := &Logger{
+		output:          &sharedOutput{writer: output},
+		ownsOutput:      true,
+		formatter:       formatter,
+		stats:           new(logStats),
+		sequence:        new(atomic.Uint64),
+		lastLogged:      new(atomic.Int64),
+		onceKeys:        new(sync.Map),
+		clock:           systemClock{},
+		ensureNewline:   true,
+		syncLevel:       OFF,
+		flushLevel:      OFF,
+		stacktraceLevel: OFF,
+		callerMinLevel:  DEBUG,
+		exitFunc:        os.Exit,
+		fatalExitCode:   DefaultFatalExitCode,
+		bufferSize:      DefaultInitialBufferSize,
 	}
+	logger.errorHandler = newStderrErrorHandler(logger.now)
+	logger.SetLevel(level)
+	logger.SetSignalReraise(true)
+	return logger
 }
 
-// SetOutput changes the output destination for the logger
+// clone returns a copy of the logger that shares its output and formatter.
+// The copy does not own the output, so shutting it down does not close the parent's file,
+// but it follows the parent when the output is replaced or reopened.
+func (l *Logger) clone() *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	hooks := make(map[LogLevel][]Hook, len(l.hooks))
+	for level, levelHooks := range l.hooks {
+		hooks[level] = append([]Hook(nil), levelHooks...)
+	}
+	byLevel := make(map[LogLevel]Formatter, len(l.byLevel))
+	for level, formatter := range l.byLevel {
+		byLevel[level] = formatter
+	}
+	child := &Logger{
+		output:          l.output,
+		formatter:       l.formatter,
+		formatName:      l.formatName,
+		byLevel:         byLevel,
+		hooks:           hooks,
+		hookPool:        l.hookPool,
+		extractors:      append([]ContextExtractor(nil), l.extractors...),
+		defaults:        l.defaults,
+		name:            l.name,
+		stats:           l.stats,
+		sequence:        l.sequence,
+		lastLogged:      l.lastLogged,
+		onceKeys:        l.onceKeys,
+		showDelta:       l.showDelta,
+		withSequence:    l.withSequence,
+		clock:           l.clock,
+		quiet:           l.quiet,
+		withGoroutineID: l.withGoroutineID,
+		ensureNewline:   l.ensureNewline,
+		syncLevel:       l.syncLevel,
+		flushLevel:      l.flushLevel,
+		stacktraceLevel: l.stacktraceLevel,
+		bufferSize:      l.bufferSize,
+		maxMessageBytes: l.maxMessageBytes,
+		maxFields:       l.maxFields,
+		callerMinLevel:  l.callerMinLevel,
+		exitFunc:        l.exitFunc,
+		fatalExitCode:   l.fatalExitCode,
+		errorHandler:    l.errorHandler,
+	}
+	child.fallback, child.fallbackThreshold = l.fallback, l.fallbackThreshold
+	child.SetLevel(l.getLevel())
+	child.packageLevels.Store(l.packageLevels.Load())
+	child.SetSignalReraise(l.reraiseSignal.Load())
+	return child
+}
+
+// SetOutput changes the output destination for the logger and the loggers derived from it
 func (l *Logger) SetOutput(output io.Writer) {
-	l.output = output
+	l.output.mu.Lock()
+	defer l.output.mu.Unlock()
+	l.output.writer = output
+	l.output.name = ""
+}
+
+// SetOutputFile switches the logger's output to the file at path, opened for appending
+// and created along with its parent directories if needed. A file previously opened by
+// the logger is closed once the new one is in place.
+func (l *Logger) SetOutputFile(path string) error {
+	file, err := openLogFile(path)
+	if err != nil {
+		return err
+	}
+	if previous := l.output.replace(file, file, path); previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// Reopen closes the log file the logger opened and opens it again at the same path,
+// creating a new file if the old one was renamed. Wire it to SIGHUP so that external
+// rotation tools such as logrotate can move the file away. Writes wait while the file is
+// reopened, so no line is lost or split, including those of loggers derived with Named,
+// WithDefaults or For. With an ErrorOutput, both files are reopened. Reopen is a no-op
+// for outputs the logger did not open from a path, such as stdout or an inherited file
+// descriptor.
+func (l *Logger) Reopen() error {
+	return l.output.reopen()
+}
+
+// reopen implements Logger.Reopen
+func (o *sharedOutput) reopen() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch writer := o.writer.(type) {
+	case *os.File:
+		reopened, err := o.reopenFile(writer)
+		if reopened == nil || err != nil {
+			return err
+		}
+		o.writer, o.closer = reopened, reopened
+		return writer.Close()
+	case *LevelWriter:
+		owned, ok := o.closer.(closers)
+		if !ok {
+			return nil
+		}
+		reopenedOutput, err := o.reopenFile(writer.output)
+		if err != nil {
+			return err
+		}
+		reopenedCopy, err := o.reopenFile(writer.copy)
+		if err != nil {
+			if reopenedOutput != nil {
+				reopenedOutput.Close()
+			}
+			return err
+		}
+		replaced := make(map[io.Closer]io.Closer)
+		levelWriter := *writer
+		if reopenedOutput != nil {
+			replaced[writer.output.(io.Closer)] = reopenedOutput
+			levelWriter.output = reopenedOutput
+		}
+		if reopenedCopy != nil {
+			replaced[writer.copy.(io.Closer)] = reopenedCopy
+			levelWriter.copy = reopenedCopy
+		}
+		if len(replaced) == 0 {
+			return nil
+		}
+		owned = append(closers(nil), owned...)
+		var errs []error
+		for i, closer := range owned {
+			if reopened, ok := replaced[closer]; ok {
+				owned[i] = reopened
+				errs = append(errs, closer.Close())
+			}
+		}
+		o.writer, o.closer = &levelWriter, owned
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// reopenFile opens a new file at the path of w if w is a file the logger opened from a
+// path itself, and returns nil otherwise. o.mu must be held.
+func (o *sharedOutput) reopenFile(w io.Writer) (*os.File, error) {
+	file, ok := w.(*os.File)
+	if !ok || strings.HasPrefix(file.Name(), fdPrefix) || !o.owns(file) {
+		return nil, nil
+	}
+	return openLogFile(file.Name())
+}
+
+// owns reports whether file is one the logger closes on Shutdown. o.mu must be held.
+func (o *sharedOutput) owns(file *os.File) bool {
+	switch closer := o.closer.(type) {
+	case *os.File:
+		return closer == file
+	case closers:
+		for _, owned := range closer {
+			if owned == io.Closer(file) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // SetLevel changes the logging level
 func (l *Logger) SetLevel(level LogLevel) {
-	l.level = level
+	l.level.Store(int32(level))
+}
+
+// getLevel returns the current logging level
+func (l *Logger) getLevel() LogLevel {
+	return LogLevel(l.level.Load())
+}
+
+// IsLevelEnabled reports whether messages at level are currently logged. With package
+// levels set by SetPackageLevel, it reports whether any package may log them.
+func (l *Logger) IsLevelEnabled(level LogLevel) bool {
+	if level >= OFF {
+		return false
+	}
+	if level >= l.getLevel() {
+		return true
+	}
+	if levels := l.packageLevels.Load(); levels != nil {
+		for _, packageLevel := range *levels {
+			if level >= packageLevel.level {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithTemporaryLevel changes the logging level and returns a function that restores the
+// previous level, so a block can be logged more verbosely:
+//
+//	defer logger.WithTemporaryLevel(log.DEBUG)()
+func (l *Logger) WithTemporaryLevel(level LogLevel) (restore func()) {
+	previous := LogLevel(l.level.Swap(int32(level)))
+	return func() {
+		l.SetLevel(previous)
+	}
+}
+
+// SetEnsureNewline controls whether a newline is appended to formatted output that does
+// not already end with one. It is enabled by default so that formatters which omit the
+// newline do not produce run-together lines.
+func (l *Logger) SetEnsureNewline(ensure bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ensureNewline = ensure
+}
+
+// SetSyncLevel makes the logger call Sync on its output after every message at or above
+// level, so that critical lines reach the operating system before a potential crash.
+// It has no effect for outputs without a Sync method. Pass OFF to disable syncing,
+// which is the default.
+func (l *Logger) SetSyncLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.syncLevel = level
+}
+
+// SetFlushLevel makes the logger flush its output after every message at or above level,
+// so that errors are written out immediately while lower levels keep being buffered.
+// It has no effect for outputs without a Flush method, see Flusher. Combine it with
+// SetSyncLevel to also commit the flushed lines to storage. Pass OFF to disable flushing,
+// which is the default.
+func (l *Logger) SetFlushLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushLevel = level
+}
+
+// SetInitialBufferSize sets the capacity the buffer used to assemble a multi-argument
+// message starts with. Raising it to the typical message size avoids reallocations
+// while large messages are built. Values below 1 restore DefaultInitialBufferSize.
+func (l *Logger) SetInitialBufferSize(size int) {
+	if size < 1 {
+		size = DefaultInitialBufferSize
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bufferSize = size
+}
+
+// SetMaxMessageBytes truncates messages longer than max bytes before they are formatted,
+// appending TruncatedMarker. The cut never splits a UTF-8 character. Pass 0 to disable
+// truncation, which is the default.
+func (l *Logger) SetMaxMessageBytes(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxMessageBytes = max
+}
+
+// SetMaxFields limits entries to max structured fields, protecting downstream systems
+// from pathological entries. Fields beyond the limit are dropped, in the order they
+// were added, and replaced by a single "_fields_truncated" field holding the number of
+// fields dropped. Fields the logger adds itself, such as "seq" or "stacktrace", are not
+// counted. Pass 0 to disable the limit, which is the default.
+func (l *Logger) SetMaxFields(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxFields = max
+}
+
+// SetCallerMinLevel limits caller lookup to messages at or above level, so that the cost
+// of walking the stack is only paid where file and line matter, such as ERROR and FATAL.
+// Formatters omit the caller from messages below level. The default, DEBUG, looks up
+// the caller for every message.
+func (l *Logger) SetCallerMinLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.callerMinLevel = level
+}
+
+// SetClock sets the clock used to timestamp entries and to measure durations: Timer
+// calls, HTTPMiddleware requests, SetQuietAfter windows, and the interval of the
+// default error handler. A nil clock restores the system clock.
+func (l *Logger) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = clock
+}
+
+// now returns the current time on the logger's clock
+func (l *Logger) now() time.Time {
+	l.mu.RLock()
+	clock := l.clock
+	l.mu.RUnlock()
+	return clock.Now()
+}
+
+// SetSequence makes the logger add a "seq" field numbering its entries 1, 2, 3 and so
+// on, so that dropped lines show up as gaps downstream. Numbers are unique even under
+// concurrent logging, but lines from different goroutines may reach the output slightly
+// out of order. Each logger created with NewLogger counts from 1.
+func (l *Logger) SetSequence(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.withSequence = enabled
+}
+
+// SetShowDelta makes the logger add a "delta" field with the time elapsed since its
+// previous entry, for example to see where a startup sequence spends its time. The first
+// entry has a delta of zero. Loggers derived with WithDefaults share the previous entry's
+// time with the logger they came from.
+func (l *Logger) SetShowDelta(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.showDelta = enabled
+}
+
+// delta records t as the time of the latest entry and returns the time elapsed since the
+// previous one. Entries logged concurrently may be timestamped out of order, in which
+// case the delta is zero rather than negative.
+func (l *Logger) delta(t time.Time) time.Duration {
+	previous := l.lastLogged.Swap(t.UnixNano())
+	if previous == 0 || t.UnixNano() < previous {
+		return 0
+	}
+	return time.Duration(t.UnixNano() - previous)
+}
+
+// SetIncludeGoroutineID makes the logger add a "goroutine" field with the ID of the
+// goroutine that logged the entry. The ID is parsed from runtime.Stack, which costs a
+// few hundred nanoseconds per entry, so it is off by default and meant for debugging.
+func (l *Logger) SetIncludeGoroutineID(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.withGoroutineID = enabled
+}
+
+// SetExitFunc replaces the function called to exit the application after a FATAL
+// message, os.Exit by default. The output is flushed before exitFunc is called.
+func (l *Logger) SetExitFunc(exitFunc func(code int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitFunc = exitFunc
+}
+
+// DefaultFatalExitCode is the exit code used after a FATAL message unless SetFatalExitCode changes it
+const DefaultFatalExitCode = 1
+
+// SetFatalExitCode sets the code passed to the exit function after a FATAL message.
+// Zero restores DefaultFatalExitCode, as a fatal message should not report success.
+func (l *Logger) SetFatalExitCode(code int) {
+	if code == 0 {
+		code = DefaultFatalExitCode
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fatalExitCode = code
+}
+
+// withFatalExitCode returns a copy of the logger that exits with code after a FATAL message
+func (l *Logger) withFatalExitCode(code int) *Logger {
+	child := l.clone()
+	child.SetFatalExitCode(code)
+	return child
+}
+
+// SetLevelFromString changes the logging level to the level named by s.
+// The level is left unchanged if s is not a known level name.
+func (l *Logger) SetLevelFromString(s string) error {
+	level, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	l.SetLevel(level)
+	return nil
 }
 
 // SetFormatter allows changing the log message format
 func (l *Logger) SetFormatter(formatter Formatter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.formatter = formatter
+	l.formatName = ""
+}
+
+// SetLevelFormatter makes messages at level use formatter instead of the logger's formatter.
+// Pass a nil formatter to remove the override.
+func (l *Logger) SetLevelFormatter(level LogLevel, formatter Formatter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if formatter == nil {
+		delete(l.byLevel, level)
+		return
+	}
+	if l.byLevel == nil {
+		l.byLevel = make(map[LogLevel]Formatter)
+	}
+	l.byLevel[level] = formatter
 }
 
-// logLevelToString converts a LogLevel to its string representation
-func logLevelToString(level LogLevel) string {
+// formatterFor returns the formatter used for messages at level; the caller must hold l.mu
+func (l *Logger) formatterFor(level LogLevel) Formatter {
+	if formatter, ok := l.byLevel[level]; ok {
+		return formatter
+	}
+	return l.formatter
+}
+
+// Flush writes out any data buffered by the output. It calls Flush if the output
+// implements Flusher, or Sync if it is a file-like output, and is a no-op otherwise.
+func (l *Logger) Flush() error {
+	switch output := l.output.get().(type) {
+	case Flusher:
+		return output.Flush()
+	case syncer:
+		return syncOutput(output)
+	default:
+		return nil
+	}
+}
+
+// Shutdown stops the logger's background work and closes what it opened: it logs the
+// summaries of pending SetQuietAfter windows, runs the hooks queued on the HookPool and
+// stops it, flushes the output, stops AsyncWriter and BatchWriter outputs, and closes any
+// file the logger opened itself. Loggers derived with Named, WithDefaults or For only
+// flush, leaving all of these to the logger they were derived from.
+// It returns ctx.Err() if ctx is done before shutdown completes. Calling
+// Shutdown more than once is a no-op.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		l.shutdownOnce.Do(func() {
+			err = l.shutdown()
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdown performs the steps of Shutdown
+func (l *Logger) shutdown() error {
+	if !l.ownsOutput {
+		return l.Flush()
+	}
+	l.mu.RLock()
+	quiet, pool := l.quiet, l.hookPool
+	l.mu.RUnlock()
+	if quiet != nil {
+		quiet.stop()
+	}
+	var errs []error
+	if pool != nil {
+		errs = append(errs, pool.Close())
+	}
+	errs = append(errs, l.Flush())
+
+	l.output.mu.RLock()
+	writer, closer := l.output.writer, l.output.closer
+	l.output.mu.RUnlock()
+	for _, background := range backgroundWriters(writer) {
+		errs = append(errs, background.Close())
+	}
+	if closer != nil {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// backgroundWriter is implemented by outputs that write from a goroutine of their own,
+// which Shutdown stops by closing them
+type backgroundWriter interface {
+	io.Closer
+	writesInBackground()
+}
+
+// backgroundWriters returns output if it is a backgroundWriter, or those it copies to
+// if it is a LevelWriter
+func backgroundWriters(output io.Writer) []backgroundWriter {
+	var found []backgroundWriter
+	writers := []io.Writer{output}
+	if levelWriter, ok := output.(*LevelWriter); ok {
+		writers = []io.Writer{levelWriter.output, levelWriter.copy}
+	}
+	for _, writer := range writers {
+		if background, ok := writer.(backgroundWriter); ok {
+			found = append(found, background)
+		}
+	}
+	return found
+}
+
+// String returns the string representation of a LogLevel
+func (level LogLevel) String() string {
 	switch level {
 	case DEBUG:
 		return "DEBUG"
 	case INFO:
 		return "INFO"
+	case NOTICE:
+		return "NOTICE"
 	case WARN:
 		return "WARN"
 	case ERROR:
 		return "ERROR"
 	case FATAL:
 		return "FATAL"
+	case OFF:
+		return "OFF"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// Clock is the source of entry timestamps and measured durations. Tests can install a
+// fixed clock with Logger.SetClock to make timestamps deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, reading the system time
+type systemClock struct{}
+
+// Now returns the current time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
 // DefaultFormatter is a simple text-based log message formatter
-type DefaultFormatter struct{}
+type DefaultFormatter struct {
+	DisableCaller bool          // Skip the caller lookup and omit file:line from the output
+	CallerFormat  CallerFormat  // How the caller's file is rendered, base name by default
+	TimePrecision TimePrecision // Sub-second precision of the timestamp, seconds by default
+}
 
 func (f *DefaultFormatter) Format(level LogLevel, message string) string {
-	_, file, line, ok := runtime.Caller(4)
-	if !ok {
-		file = "unknown"
-		line = 0
+	return f.FormatEntry(newEntry(level, message, f.reportsCaller()))
+}
+
+// FormatEntry formats an entry as a single line of text
+func (f *DefaultFormatter) FormatEntry(e Entry) string {
+	timestamp := e.Time.Format(textTimeLayout(f.TimePrecision))
+	if f.DisableCaller || e.skipCaller {
+		return fmt.Sprintf("%s - [%s] %s%s\n", timestamp, e.Level.String(), e.Message, textFields(e.Fields))
+	}
+	return fmt.Sprintf("%s - %s:%d - [%s] %s%s\n", timestamp, callerFile(e, f.CallerFormat), e.Line, e.Level.String(), e.Message, textFields(e.Fields))
+}
+
+// textFields renders fields as a " key=value key2=value2" suffix in insertion order,
+// quoting values that are empty or contain spaces, quotes, or equals signs
+func textFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteByte(' ')
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		b.WriteString(textValue(field.Value))
+	}
+	return b.String()
+}
+
+// textValue renders a field value, quoting it when it would be ambiguous unquoted.
+// Times are written in RFC 3339 like the JSON formatter writes them.
+func textValue(value interface{}) string {
+	var s string
+	if t, ok := value.(time.Time); ok {
+		s = t.Format(time.RFC3339Nano)
+	} else {
+		s = fmt.Sprint(value)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
 	}
-	file = filepath.Base(file)
-	now := time.Now().Format("2006-01-02 15:04:05")
-	return fmt.Sprintf("%s - %s:%d - [%s] %s\n", now, file, line, logLevelToString(level), message)
+	return s
+}
+
+func (f *DefaultFormatter) reportsCaller() bool {
+	return !f.DisableCaller
 }
 
 // JSONFormatter formats log messages as JSON
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	DisableCaller bool          // Skip the caller lookup and omit the file and line fields
+	CallerFormat  CallerFormat  // How the caller's file is rendered, base name by default
+	TimePrecision TimePrecision // Sub-second precision of the timestamp, seconds by default
+	UTC           bool          // Write timestamps in UTC instead of local time
+	NumericLevel  bool          // Also write the level as a number in the "level_num" field
+	LevelScheme   LevelScheme   // Numeric codes used by NumericLevel, Bunyan by default
+	OmitEmpty     bool          // Drop the caller when it is unknown, and an empty timestamp or message
+	NestedCaller  bool          // Group the caller's file, line and function under a "caller" object
+
+	// NumericLevelKey renames the "level_num" key written with NumericLevel, for example
+	// to "level_value" for pipelines that expect it. Setting it to "level" writes the
+	// number in place of the level name, as Bunyan does. The other standard keys are
+	// reserved and fall back to "level_num".
+	NumericLevelKey string
+
+	// DurationFormat controls how time.Duration fields are written, as integer
+	// nanoseconds by default. FormatTimeFields writes time.Time fields with the
+	// timestamp's precision and UTC setting instead of RFC 3339 with nanoseconds.
+	DurationFormat   DurationFormat
+	FormatTimeFields bool
+
+	// FieldAllowlist, when non-nil, limits structured fields to the listed keys; any other
+	// field is dropped. The timestamp, level, message and caller are always written.
+	FieldAllowlist []string
+}
 
 func (f *JSONFormatter) Format(level LogLevel, message string) string {
-	_, file, line, ok := runtime.Caller(4)
-	if !ok {
-		file = "unknown"
-		line = 0
-	}
-	file = filepath.Base(file)
-	now := time.Now().Format(time.RFC3339)
-	logEntry := map[string]interface{}{
-		"timestamp": now,
-		"level":     logLevelToString(level),
-		"file":      file,
-		"line":      line,
-		"message":   message,
-	}
-	jsonLog, err := json.Marshal(logEntry)
-	if err != nil {
-		return fmt.Sprintf(`{"error": "failed to format log message", "message": "%s"}`, message)
+	return f.FormatEntry(newEntry(level, message, f.reportsCaller()))
+}
+
+// jsonNestedCaller holds the caller keys written under "caller" with NestedCaller
+type jsonNestedCaller struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// jsonObject writes a JSON object to buf one key at a time, in the order the keys are
+// added, remembering them so that no key is written twice
+type jsonObject struct {
+	buf     *bytes.Buffer
+	encoder *json.Encoder
+	keys    []string
+	err     error
+}
+
+// has reports whether key has already been written
+func (o *jsonObject) has(key string) bool {
+	for _, written := range o.keys {
+		if written == key {
+			return true
+		}
 	}
-	return string(jsonLog)
+	return false
 }
 
-// log logs a message using the current formatter
-func (l *Logger) log(level LogLevel, v ...interface{}) {
-	if level < l.level {
+// add writes a key and its value, doing nothing once a value has failed to encode
+func (o *jsonObject) add(key string, value interface{}) {
+	if o.err != nil {
 		return
 	}
-	message := fmt.Sprint(v...)
-	formattedMessage := l.formatter.Format(level, message)
-	fmt.Fprint(l.output, formattedMessage)
+	if len(o.keys) == 0 {
+		o.buf.WriteByte('{')
+	} else {
+		o.buf.WriteByte(',')
+	}
+	o.keys = append(o.keys, key)
+	// The encoder terminates every value with a newline, which is dropped each time
+	o.encoder.Encode(key)
+	o.buf.Truncate(o.buf.Len() - 1)
+	o.buf.WriteByte(':')
+	if o.err = o.encoder.Encode(value); o.err == nil {
+		o.buf.Truncate(o.buf.Len() - 1)
+	}
+}
+
+// FormatEntry formats an entry as JSON, adding its structured fields as top-level keys
+// after the standard ones, in the order they were added.
+// A field whose key clashes with a standard key is written as "fields.<key>".
+func (f *JSONFormatter) FormatEntry(e Entry) string {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+	buf.Reset()
+	object := jsonObject{buf: buf, encoder: json.NewEncoder(buf)}
+
+	levelKey := f.numericLevelKey()
+	if !(f.OmitEmpty && e.Time.IsZero()) {
+		object.add("timestamp", f.timestamp(e))
+	}
+	if f.NumericLevel && levelKey == "level" {
+		object.add("level", f.LevelScheme.Value(e.Level))
+	} else {
+		object.add("level", e.Level.String())
+	}
+	if f.NumericLevel && levelKey != "level" {
+		object.add(levelKey, f.LevelScheme.Value(e.Level))
+	}
+	if !(f.OmitEmpty && e.Message == "") {
+		object.add("message", e.Message)
+	}
+	if !f.DisableCaller && !e.skipCaller && !(f.OmitEmpty && e.File == "") {
+		if f.NestedCaller {
+			object.add("caller", jsonNestedCaller{File: callerFile(e, f.CallerFormat), Line: e.Line, Function: e.Function})
+		} else {
+			object.add("file", callerFile(e, f.CallerFormat))
+			object.add("line", e.Line)
+		}
+	}
+	for _, field := range e.Fields {
+		if !f.allowed(field.Key) {
+			continue
+		}
+		key := field.Key
+		for f.isStandardKey(key) || object.has(key) {
+			key = "fields." + key
+		}
+		object.add(key, f.fieldValue(field.Value))
+	}
+	if object.err != nil {
+		// Only strings remain, so this marshal cannot fail and the message stays escaped
+		jsonLog, _ := json.Marshal(map[string]string{
+			"timestamp": f.timestamp(e),
+			"level":     e.Level.String(),
+			"message":   e.Message,
+			"error":     "failed to format log message: " + object.err.Error(),
+		})
+		return string(jsonLog)
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// isStandardKey reports whether key is written by the formatter itself
+func (f *JSONFormatter) isStandardKey(key string) bool {
+	switch key {
+	case "timestamp", "level", "message":
+		return true
+	case f.numericLevelKey():
+		return f.NumericLevel
+	case "file", "line":
+		return !f.DisableCaller && !f.NestedCaller
+	case "caller":
+		return !f.DisableCaller && f.NestedCaller
+	}
+	return false
+}
+
+// numericLevelKey returns the key of the numeric level, NumericLevelKey unless it is
+// empty or another standard key, in which case "level_num"
+func (f *JSONFormatter) numericLevelKey() string {
+	switch f.NumericLevelKey {
+	case "", "timestamp", "message", "file", "line", "caller":
+		return "level_num"
+	}
+	return f.NumericLevelKey
+}
+
+// timestamp formats the entry's time with the formatter's precision and time zone
+func (f *JSONFormatter) timestamp(e Entry) string {
+	t := e.Time
+	if f.UTC {
+		t = t.UTC()
+	}
+	return t.Format(rfc3339Layout(f.TimePrecision))
+}
+
+// fieldValue applies DurationFormat and FormatTimeFields to a field value
+func (f *JSONFormatter) fieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Duration:
+		if f.DurationFormat == DurationString {
+			return v.String()
+		}
+	case time.Time:
+		if f.FormatTimeFields {
+			if f.UTC {
+				v = v.UTC()
+			}
+			return v.Format(rfc3339Layout(f.TimePrecision))
+		}
+	}
+	return value
+}
+
+// allowed reports whether a structured field passes the FieldAllowlist
+func (f *JSONFormatter) allowed(key string) bool {
+	if f.FieldAllowlist == nil {
+		return true
+	}
+	for _, allowed := range f.FieldAllowlist {
+		if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *JSONFormatter) reportsCaller() bool {
+	return !f.DisableCaller
+}
+
+// outputWantsCaller reports whether an output formats entries itself and renders caller information
+func outputWantsCaller(output io.Writer) bool {
+	reporter, ok := output.(callerReporter)
+	return ok && reporter.reportsCaller()
+}
+
+// log logs a message and its structured fields using the current formatter
+func (l *Logger) log(level LogLevel, fields []Field, v ...interface{}) {
+	l.logTo(nil, 0, level, fields, v...)
+}
+
+// logTo is log, additionally writing the formatted entry to the extra writers and
+// reporting the caller callerSkip frames above the logging call
+func (l *Logger) logTo(extra []io.Writer, callerSkip int, level LogLevel, fields []Field, v ...interface{}) {
+	if level >= OFF {
+		// OFF is a threshold, not a severity; nothing is ever logged at it
+		return
+	}
+	var frame runtime.Frame
+	var haveFrame bool
+	if levels := l.packageLevels.Load(); levels != nil {
+		frame, haveFrame = captureCaller(0, callerSkip)
+		if level < levelFor(*levels, frame.Function, l.getLevel()) {
+			return
+		}
+	} else if level < l.getLevel() {
+		return
+	}
+	l.mu.RLock()
+	formatter, bufferSize, maxMessageBytes, maxFields := l.formatterFor(level), l.bufferSize, l.maxMessageBytes, l.maxFields
+	clock, quiet, fields := l.clock, l.quiet, l.withDefaults(fields)
+	withSequence, showDelta, withGoroutineID := l.withSequence, l.showDelta, l.withGoroutineID
+	stacktraceLevel, callerMinLevel, ensureNewline := l.stacktraceLevel, l.callerMinLevel, l.ensureNewline
+	errorHandler, flushLevel, syncLevel := l.errorHandler, l.flushLevel, l.syncLevel
+	exitFunc, fatalExitCode := l.exitFunc, l.fatalExitCode
+	l.mu.RUnlock()
+	output := l.output.get()
+
+	entry := Entry{
+		logger:  l,
+		Time:    clock.Now(),
+		Level:   level,
+		Message: truncate(sprint(bufferSize, v...), maxMessageBytes),
+		Fields:  limitFields(fields, maxFields),
+	}
+	if quiet != nil && !quiet.allow(l, clock, entry) {
+		return
+	}
+	if withSequence {
+		entry.Fields = setField(append([]Field(nil), entry.Fields...), Field{Key: "seq", Value: l.sequence.Add(1)})
+	}
+	if showDelta {
+		entry.Fields = setField(append([]Field(nil), entry.Fields...), Field{Key: "delta", Value: l.delta(entry.Time)})
+	}
+	if withGoroutineID {
+		entry.Fields = setField(append([]Field(nil), entry.Fields...), Field{Key: "goroutine", Value: goroutineID()})
+	}
+	if level >= stacktraceLevel {
+		entry.Fields = setField(append([]Field(nil), entry.Fields...), Field{Key: "stacktrace", Value: captureStacktrace(1)})
+	}
+	if wantsCaller(formatter) || outputWantsCaller(output) {
+		switch {
+		case level < callerMinLevel:
+			entry.skipCaller = true
+		case haveFrame:
+			// Already looked up for the package level check
+			entry.File, entry.Line, entry.Function = frame.File, frame.Line, frame.Function
+		default:
+			entry.setCaller(1, callerSkip)
+		}
+	}
+	l.fireHooks(entry)
+	formatted := format(formatter, entry)
+	if ensureNewline && formatted != "" && !strings.HasSuffix(formatted, "\n") && !isBinary(formatter) {
+		formatted += "\n"
+	}
+	// Write under the read lock so that Reopen cannot close the output in the middle of a write
+	var err error
+	l.output.mu.RLock()
+	output = l.output.writer
+	if writer, ok := output.(EntryWriter); ok {
+		err = writer.WriteEntry(entry, formatted)
+	} else {
+		_, err = io.WriteString(output, formatted)
+	}
+	l.output.mu.RUnlock()
+	l.stats.record(err)
+	if err != nil {
+		errorHandler(err)
+		l.failOver(formatted)
+	} else {
+		l.outputFailures.Store(0)
+	}
+	for _, w := range extra {
+		io.WriteString(w, formatted)
+	}
+	if level >= flushLevel {
+		if output, ok := output.(Flusher); ok {
+			output.Flush()
+		}
+	}
+	if level >= syncLevel {
+		if output, ok := output.(syncer); ok {
+			syncOutput(output)
+		}
+	}
 
 	if level == FATAL {
-		os.Exit(1)
+		// Make sure the fatal message is not lost in a buffer when the process exits
+		l.Flush()
+		exitFunc(fatalExitCode)
+	}
+}
+
+// DefaultInitialBufferSize is the capacity message buffers start with unless SetInitialBufferSize changes it
+const DefaultInitialBufferSize = 256
+
+// maxPooledBufferSize keeps buffers grown by unusually large messages from being pooled and pinning memory
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool holds buffers reused to assemble multi-argument messages
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// sprint is equivalent to fmt.Sprint but returns a single string argument as-is
+// and assembles other messages in a pooled buffer of at least size bytes
+func sprint(size int, v ...interface{}) (message string) {
+	if len(v) == 1 {
+		if s, ok := v[0].(string); ok {
+			return s
+		}
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.Grow(size)
+	// fmt reports a panicking String or Error method inline, but re-panics when printing
+	// the panic value panics too; a log call must not crash the program either way
+	defer func() {
+		if r := recover(); r != nil {
+			putBuffer(buf)
+			message = fmt.Sprintf("(PANIC formatting log message: %s)", describePanic(r))
+		}
+	}()
+	// Strings are copied straight into the buffer rather than through fmt's own buffer,
+	// keeping fmt.Sprint's rule of adding spaces only between two non-string operands
+	previousString := true
+	for i, arg := range v {
+		isString := arg != nil && reflect.TypeOf(arg).Kind() == reflect.String
+		if i > 0 && !isString && !previousString {
+			buf.WriteByte(' ')
+		}
+		if s, ok := arg.(string); ok {
+			buf.WriteString(s)
+		} else {
+			fmt.Fprint(buf, arg)
+		}
+		previousString = isString
+	}
+	message = buf.String()
+	putBuffer(buf)
+	return message
+}
+
+// describePanic renders a recovered panic value without calling any of its methods
+func describePanic(r interface{}) string {
+	if s, ok := r.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+// putBuffer returns buf to bufferPool unless it has grown past maxPooledBufferSize
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// TruncatedMarker is appended to messages shortened by SetMaxMessageBytes
+const TruncatedMarker = "…(truncated)"
+
+// truncate shortens message to at most max bytes followed by TruncatedMarker, backing up
+// to the start of a UTF-8 character so none is split. A max of 0 or less disables it.
+func truncate(message string, max int) string {
+	if max <= 0 || len(message) <= max {
+		return message
 	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + TruncatedMarker
+}
+
+// fieldsTruncatedKey is the field that reports how many fields SetMaxFields dropped
+const fieldsTruncatedKey = "_fields_truncated"
+
+// limitFields keeps the first max fields and adds a _fields_truncated marker with the
+// number dropped. A max of 0 or less disables it.
+func limitFields(fields []Field, max int) []Field {
+	if max <= 0 || len(fields) <= max {
+		return fields
+	}
+	limited := make([]Field, max, max+1)
+	copy(limited, fields)
+	return append(limited, Field{Key: fieldsTruncatedKey, Value: len(fields) - max})
+}
+
+// format renders an entry, preferring FormatEntry when the formatter implements EntryFormatter
+func format(formatter Formatter, e Entry) string {
+	if formatter, ok := formatter.(EntryFormatter); ok {
+		return formatter.FormatEntry(e)
+	}
+	return formatter.Format(e.Level, e.Message)
+}
+
+// Log logs a message at the given level
+func (l *Logger) Log(level LogLevel, v ...interface{}) {
+	l.log(level, nil, v...)
 }
 
 // Debug logs a debug message
 func (l *Logger) Debug(v ...interface{}) {
-	l.log(DEBUG, v...)
+	l.log(DEBUG, nil, v...)
 }
 
 // Info logs an info message
 func (l *Logger) Info(v ...interface{}) {
-	l.log(INFO, v...)
+	l.log(INFO, nil, v...)
+}
+
+// Notice logs a notice message for normal but significant events
+func (l *Logger) Notice(v ...interface{}) {
+	l.log(NOTICE, nil, v...)
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(v ...interface{}) {
-	l.log(WARN, v...)
+	l.log(WARN, nil, v...)
 }
 
 // Error logs an error message
 func (l *Logger) Error(v ...interface{}) {
-	l.log(ERROR, v...)
+	l.log(ERROR, nil, v...)
 }
 
-// Fatal logs a fatal message and exits the application
+// Fatal logs a fatal message, flushes the output and exits the application
 func (l *Logger) Fatal(v ...interface{}) {
-	l.log(FATAL, v...)
+	l.log(FATAL, nil, v...)
+}
+
+// FatalWithCode logs a fatal message, flushes the output and exits the application
+// with code instead of the logger's fatal exit code
+func (l *Logger) FatalWithCode(code int, v ...interface{}) {
+	l.withFatalExitCode(code).log(FATAL, nil, v...)
+}
+
+// DebugIf logs a debug message only when cond is true
+func (l *Logger) DebugIf(cond bool, v ...interface{}) {
+	if cond {
+		l.log(DEBUG, nil, v...)
+	}
+}
+
+// InfoIf logs an info message only when cond is true
+func (l *Logger) InfoIf(cond bool, v ...interface{}) {
+	if cond {
+		l.log(INFO, nil, v...)
+	}
+}
+
+// NoticeIf logs a notice message only when cond is true
+func (l *Logger) NoticeIf(cond bool, v ...interface{}) {
+	if cond {
+		l.log(NOTICE, nil, v...)
+	}
+}
+
+// WarnIf logs a warning message only when cond is true
+func (l *Logger) WarnIf(cond bool, v ...interface{}) {
+	if cond {
+		l.log(WARN, nil, v...)
+	}
+}
+
+// ErrorIf logs an error message only when cond is true
+func (l *Logger) ErrorIf(cond bool, v ...interface{}) {
+	if cond {
+		l.log(ERROR, nil, v...)
+	}
+}
+
+// FatalIf logs a fatal message and exits the application only when cond is true
+func (l *Logger) FatalIf(cond bool, v ...interface{}) {
+	if cond {
+		l.log(FATAL, nil, v...)
+	}
+}
+
+// DebugFunc logs a debug message built by fn, which is only called when DEBUG is enabled
+func (l *Logger) DebugFunc(fn func() string) {
+	l.logFunc(DEBUG, fn)
+}
+
+// InfoFunc logs an info message built by fn, which is only called when INFO is enabled
+func (l *Logger) InfoFunc(fn func() string) {
+	l.logFunc(INFO, fn)
+}
+
+// NoticeFunc logs a notice message built by fn, which is only called when NOTICE is enabled
+func (l *Logger) NoticeFunc(fn func() string) {
+	l.logFunc(NOTICE, fn)
+}
+
+// WarnFunc logs a warning message built by fn, which is only called when WARN is enabled
+func (l *Logger) WarnFunc(fn func() string) {
+	l.logFunc(WARN, fn)
+}
+
+// ErrorFunc logs an error message built by fn, which is only called when ERROR is enabled
+func (l *Logger) ErrorFunc(fn func() string) {
+	l.logFunc(ERROR, fn)
+}
+
+// FatalFunc logs a fatal message built by fn and exits the application.
+// fn is only called, and the application only exits, when FATAL is enabled.
+func (l *Logger) FatalFunc(fn func() string) {
+	l.logFunc(FATAL, fn)
+}
+
+// logFunc logs the message returned by fn, calling fn only if level is enabled
+func (l *Logger) logFunc(level LogLevel, fn func() string) {
+	if l.IsLevelEnabled(level) {
+		l.log(level, nil, fn())
+	}
 }
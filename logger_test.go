@@ -1,13 +1,24 @@
 package log_test
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
 )
 
 // TestNewLogger verifies that a new logger instance is created correctly with the default formatter
@@ -46,6 +57,46 @@ func TestLogger_Info(t *testing.T) {
 	}
 }
 
+// TestLogger_Notice verifies that the logger filters at the NOTICE threshold
+func TestLogger_Notice(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.NOTICE, &log.DefaultFormatter{})
+
+	logger.Info("Info message")
+	if buf.String() != "" {
+		t.Errorf("Expected no output for Info message when level is NOTICE, got %v", buf.String())
+	}
+
+	logger.Notice("Notice message")
+	if !containsLogMessage(buf.String(), "NOTICE", "Notice message") {
+		t.Errorf("Expected 'NOTICE - Notice message' in output, got %v", buf.String())
+	}
+
+	buf.Reset()
+	logger.Warn("Warn message")
+	if !containsLogMessage(buf.String(), "WARN", "Warn message") {
+		t.Errorf("Expected 'WARN - Warn message' in output, got %v", buf.String())
+	}
+}
+
+// TestLogLevel_String verifies the string representation of each level
+func TestLogLevel_String(t *testing.T) {
+	levels := map[log.LogLevel]string{
+		log.DEBUG:  "DEBUG",
+		log.INFO:   "INFO",
+		log.NOTICE: "NOTICE",
+		log.WARN:   "WARN",
+		log.ERROR:  "ERROR",
+		log.FATAL:  "FATAL",
+		log.OFF:    "OFF",
+	}
+	for level, expected := range levels {
+		if level.String() != expected {
+			t.Errorf("Expected '%v', got '%v'", expected, level.String())
+		}
+	}
+}
+
 // TestLogger_Warn verifies that the logger logs warning messages when the level is WARN
 func TestLogger_Warn(t *testing.T) {
 	var buf bytes.Buffer
@@ -83,6 +134,521 @@ func TestLogger_SetLevel(t *testing.T) {
 	}
 }
 
+// TestLogger_Off verifies that no method produces output when the level is OFF, and that Fatal does not exit
+func TestLogger_Off(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.OFF, &log.DefaultFormatter{})
+
+	logger.Debug("Debug message")
+	logger.Info("Info message")
+	logger.Notice("Notice message")
+	logger.Warn("Warn message")
+	logger.Error("Error message")
+	logger.Fatal("Fatal message")
+
+	if buf.String() != "" {
+		t.Errorf("Expected no output when level is OFF, got %v", buf.String())
+	}
+}
+
+// TestLogger_LogOff verifies that logging at OFF itself writes nothing, even at the lowest level
+func TestLogger_LogOff(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.DEBUG, &log.DefaultFormatter{})
+
+	logger.Log(log.OFF, "Off message")
+	logger.WithField("key", "value").Log(log.OFF, "Off entry message")
+
+	if buf.String() != "" {
+		t.Errorf("Expected no output when logging at OFF, got %v", buf.String())
+	}
+	if logger.IsLevelEnabled(log.OFF) {
+		t.Errorf("Expected OFF never to be enabled")
+	}
+}
+
+// TestLogger_SettersConcurrent verifies that settings can change while another goroutine logs; run with -race
+func TestLogger_SettersConcurrent(t *testing.T) {
+	logger := log.NewLogger(io.Discard, log.DEBUG, &log.JSONFormatter{})
+	logger.SetExitFunc(func(int) {})
+
+	var wg sync.WaitGroup
+	started, done := make(chan struct{}), make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("First message")
+		close(started)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				logger.WithContext(context.Background()).Error("Concurrent message")
+				logger.Timer("Timed")()
+			}
+		}
+	}()
+	<-started
+	for i := 0; i < 50; i++ {
+		enabled := i%2 == 0
+		logger.SetSequence(enabled)
+		logger.SetEnsureNewline(enabled)
+		logger.SetSyncLevel(log.ERROR)
+		logger.SetFlushLevel(log.ERROR)
+		logger.SetStacktraceLevel(log.FATAL)
+		logger.AddContextExtractor(func(ctx context.Context) log.Fields { return nil })
+		logger.SetCallerMinLevel(log.WARN)
+		logger.SetExitFunc(func(int) {})
+		logger.SetIncludeGoroutineID(enabled)
+		logger.SetClock(nil)
+		logger.SetBuildInfo("v1.0.0", fmt.Sprint(i))
+		logger.SetHookPool(nil)
+		logger.SetQuietAfter(100, time.Minute)
+		logger.SetFatalExitCode(i + 1)
+		logger.SetShowDelta(enabled)
+		logger.SetErrorHandler(func(error) {})
+	}
+	close(done)
+	wg.Wait()
+}
+
+// flushWriter is a test writer that records Flush calls
+type flushWriter struct {
+	bytes.Buffer
+	flushed int
+	err     error
+}
+
+func (w *flushWriter) Flush() error {
+	w.flushed++
+	return w.err
+}
+
+// TestLogger_Flush verifies that Flush is forwarded to a flushable output
+func TestLogger_Flush(t *testing.T) {
+	writer := &flushWriter{}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Info message")
+	if err := logger.Flush(); err != nil {
+		t.Errorf("Expected no error from Flush, got %v", err)
+	}
+
+	if writer.flushed != 1 {
+		t.Errorf("Expected output to be flushed once, got %d", writer.flushed)
+	}
+}
+
+// TestLogger_FlushError verifies that errors from the output's Flush are returned
+func TestLogger_FlushError(t *testing.T) {
+	writer := &flushWriter{err: errors.New("flush failed")}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+
+	if err := logger.Flush(); err != writer.err {
+		t.Errorf("Expected error %v from Flush, got %v", writer.err, err)
+	}
+}
+
+// TestLogger_FlushNoop verifies that Flush is a no-op for outputs that cannot be flushed
+func TestLogger_FlushNoop(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	if err := logger.Flush(); err != nil {
+		t.Errorf("Expected no error from Flush, got %v", err)
+	}
+}
+
+// TestLogger_FlushPipe verifies that Flush succeeds when the output is a pipe, which can't be synced
+func TestLogger_FlushPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	logger := log.NewLogger(w, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Piped message")
+	if err := logger.Flush(); err != nil {
+		t.Errorf("Expected no error from Flush, got %v", err)
+	}
+}
+
+// blockingFlusher is a test writer whose Flush blocks until released
+type blockingFlusher struct {
+	bytes.Buffer
+	release chan struct{}
+}
+
+func (w *blockingFlusher) Flush() error {
+	<-w.release
+	return nil
+}
+
+// TestLogger_Shutdown verifies that Shutdown writes out buffered data and that a second call is a no-op
+func TestLogger_Shutdown(t *testing.T) {
+	var buf bytes.Buffer
+	writer := &countingBufferedWriter{Writer: bufio.NewWriter(&buf)}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Buffered message")
+	if buf.String() != "" {
+		t.Fatalf("Expected message to be buffered before Shutdown, got %v", buf.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := logger.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected no error from Shutdown, got %v", err)
+	}
+	if !containsLogMessage(buf.String(), "INFO", "Buffered message") {
+		t.Errorf("Expected buffered message to be written on Shutdown, got %v", buf.String())
+	}
+
+	if err := logger.Shutdown(ctx); err != nil {
+		t.Errorf("Expected no error from second Shutdown, got %v", err)
+	}
+	if writer.flushed != 1 {
+		t.Errorf("Expected output to be flushed once, got %d", writer.flushed)
+	}
+}
+
+// TestLogger_ShutdownHookPool verifies that Shutdown runs the hooks still queued on the hook pool
+func TestLogger_ShutdownHookPool(t *testing.T) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{})
+	hook := newBlockingHook()
+	logger.AddHook(hook)
+	pool := log.NewHookPool(1, 8, log.HookDrop)
+	logger.SetHookPool(pool)
+
+	logger.Error("First alert")
+	logger.Error("Second alert")
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(hook.release)
+	}()
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error from Shutdown, got %v", err)
+	}
+
+	if fired := len(hook.fired); fired != 2 {
+		t.Errorf("Expected both queued hooks to run before Shutdown returned, got %d", fired)
+	}
+	logger.Error("After shutdown")
+	if dropped := pool.Dropped(); dropped != 1 {
+		t.Errorf("Expected the hook pool to be closed, got %d dropped entries", dropped)
+	}
+}
+
+// TestLogger_ShutdownBackgroundWriters verifies that Shutdown writes out and stops AsyncWriter and BatchWriter outputs
+func TestLogger_ShutdownBackgroundWriters(t *testing.T) {
+	var buf bytes.Buffer
+	async := log.NewAsyncWriter(&buf, 8)
+	recorder := newPayloadRecorder()
+	batch := log.NewBatchWriter(recorder.send, log.BatchOptions{BatchTimeout: time.Hour})
+	logger := log.NewLogger(log.NewLevelWriter(async, log.ERROR, batch), log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.Error("Queued")
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error from Shutdown, got %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Queued") {
+		t.Errorf("Expected the async entry to be written, got %q", buf.String())
+	}
+	if payloads := recorder.Payloads(); len(payloads) != 1 || !strings.Contains(payloads[0], "Queued") {
+		t.Errorf("Expected the batch to be sent, got %v", payloads)
+	}
+	if _, err := async.Write([]byte("late\n")); err != log.ErrWriterClosed {
+		t.Errorf("Expected the AsyncWriter to be closed, got %v", err)
+	}
+	if _, err := batch.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected the BatchWriter to be closed, got %v", err)
+	}
+}
+
+// TestLogger_ShutdownQuiet verifies that Shutdown logs the summaries of pending quiet windows and stops counting
+func TestLogger_ShutdownQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.SetQuietAfter(1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		logger.Info("Waiting for dependency")
+	}
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error from Shutdown, got %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Waiting for dependency (suppressed 2 more times in 1h0m0s)") {
+		t.Errorf("Expected the summary to be logged on Shutdown, got %q", buf.String())
+	}
+	if count := logger.QuietMessages(); count != 0 {
+		t.Errorf("Expected no messages to be counted after Shutdown, got %d", count)
+	}
+}
+
+// TestLogger_ShutdownTimeout verifies that Shutdown gives up when the context expires
+func TestLogger_ShutdownTimeout(t *testing.T) {
+	writer := &blockingFlusher{release: make(chan struct{})}
+	defer close(writer.release)
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := logger.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v from Shutdown, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestLogger_ShutdownPipe verifies that Shutdown succeeds when the output is a pipe, such as a redirected stdout
+func TestLogger_ShutdownPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	logger := log.NewLogger(w, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Piped message")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := logger.Shutdown(ctx); err != nil {
+		t.Errorf("Expected no error from Shutdown, got %v", err)
+	}
+}
+
+// countingBufferedWriter is a bufio.Writer that records Flush calls
+type countingBufferedWriter struct {
+	*bufio.Writer
+	flushed int
+}
+
+func (w *countingBufferedWriter) Flush() error {
+	w.flushed++
+	return w.Writer.Flush()
+}
+
+// TestLogger_MultiArgMessage verifies that multi-argument messages match fmt.Sprint
+func TestLogger_MultiArgMessage(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &MyCustomFormatter{})
+
+	args := []interface{}{"count:", 1, 2, "done", errors.New("err"), 3.5}
+	logger.Info(args...)
+
+	expected := fmt.Sprintf("**CUSTOM LOG** [INFO] %s\n", fmt.Sprint(args...))
+	if buf.String() != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// TestLogger_InfoIf verifies that conditional logging only writes when the condition is true
+func TestLogger_InfoIf(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	logger.InfoIf(false, "Skipped message")
+	if buf.String() != "" {
+		t.Errorf("Expected no output when condition is false, got %v", buf.String())
+	}
+
+	logger.InfoIf(true, "Conditional message")
+	if !containsLogMessage(buf.String(), "INFO", "Conditional message") {
+		t.Errorf("Expected 'INFO - Conditional message' in output, got %v", buf.String())
+	}
+}
+
+// TestLogger_DebugIf verifies that conditional logging still respects the logger level
+func TestLogger_DebugIf(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	logger.DebugIf(true, "Filtered message")
+
+	if buf.String() != "" {
+		t.Errorf("Expected no output for DebugIf when level is INFO, got %v", buf.String())
+	}
+}
+
+// TestLogger_DebugFunc verifies that the message function only runs when DEBUG is enabled
+func TestLogger_DebugFunc(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+	called := false
+	dump := func() string {
+		called = true
+		return "Expensive dump"
+	}
+
+	logger.DebugFunc(dump)
+	if called || buf.String() != "" {
+		t.Errorf("Expected DebugFunc not to run at INFO level, got called=%v output %v", called, buf.String())
+	}
+
+	logger.SetLevel(log.DEBUG)
+	logger.DebugFunc(dump)
+	if !called || !containsLogMessage(buf.String(), "DEBUG", "Expensive dump") {
+		t.Errorf("Expected DebugFunc to run at DEBUG level, got called=%v output %v", called, buf.String())
+	}
+}
+
+// TestLogger_IsLevelEnabled verifies that levels at or above the logger level are enabled
+func TestLogger_IsLevelEnabled(t *testing.T) {
+	logger := log.NewLogger(io.Discard, log.WARN, &log.DefaultFormatter{})
+
+	if logger.IsLevelEnabled(log.INFO) {
+		t.Errorf("Expected INFO to be disabled at WARN level")
+	}
+	if !logger.IsLevelEnabled(log.WARN) || !logger.IsLevelEnabled(log.ERROR) {
+		t.Errorf("Expected WARN and ERROR to be enabled at WARN level")
+	}
+}
+
+// bareFormatter is a test formatter that does not terminate its output with a newline
+type bareFormatter struct{}
+
+func (f *bareFormatter) Format(level log.LogLevel, message string) string {
+	return fmt.Sprintf("[%s] %s", level, message)
+}
+
+// TestLogger_EnsureNewline verifies that a newline is added only when the formatter omits it
+func TestLogger_EnsureNewline(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &bareFormatter{})
+
+	logger.Info("First")
+	logger.SetFormatter(&MyCustomFormatter{})
+	logger.Info("Second")
+
+	expected := "[INFO] First\n**CUSTOM LOG** [INFO] Second\n"
+	if buf.String() != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// TestLogger_EnsureNewlineDisabled verifies that output is written as-is when the option is off
+func TestLogger_EnsureNewlineDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &bareFormatter{})
+	logger.SetEnsureNewline(false)
+
+	logger.Info("First")
+	logger.Info("Second")
+
+	expected := "[INFO] First[INFO] Second"
+	if buf.String() != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// syncWriter is a test writer that records Sync calls like an *os.File
+type syncWriter struct {
+	bytes.Buffer
+	synced int
+}
+
+func (w *syncWriter) Sync() error {
+	w.synced++
+	return nil
+}
+
+// TestLogger_SetSyncLevel verifies that the output is synced after ERROR but not after INFO
+func TestLogger_SetSyncLevel(t *testing.T) {
+	writer := &syncWriter{}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+	logger.SetSyncLevel(log.ERROR)
+
+	logger.Info("Info message")
+	if writer.synced != 0 {
+		t.Errorf("Expected no sync after Info, got %d", writer.synced)
+	}
+
+	logger.Error("Error message")
+	if writer.synced != 1 {
+		t.Errorf("Expected one sync after Error, got %d", writer.synced)
+	}
+}
+
+// TestLogger_SetFlushLevel verifies that a buffered output is flushed after an Error but not after an Info
+func TestLogger_SetFlushLevel(t *testing.T) {
+	var buf bytes.Buffer
+	writer := bufio.NewWriter(&buf)
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+	logger.SetFlushLevel(log.ERROR)
+
+	logger.Info("Info message")
+	if writer.Buffered() == 0 || buf.Len() != 0 {
+		t.Errorf("Expected the Info message to stay buffered, got %d bytes written", buf.Len())
+	}
+
+	logger.Error("Error message")
+	if writer.Buffered() != 0 {
+		t.Errorf("Expected an empty buffer after Error, got %d buffered bytes", writer.Buffered())
+	}
+	if !strings.Contains(buf.String(), "Info message") || !strings.Contains(buf.String(), "Error message") {
+		t.Errorf("Expected both messages to be written after Error, got %q", buf.String())
+	}
+}
+
+// TestLogger_SyncDisabledByDefault verifies that the output is never synced unless a sync level is set
+func TestLogger_SyncDisabledByDefault(t *testing.T) {
+	writer := &syncWriter{}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+
+	logger.Error("Error message")
+
+	if writer.synced != 0 {
+		t.Errorf("Expected no sync by default, got %d", writer.synced)
+	}
+}
+
+// TestLogger_WithTemporaryLevel verifies that the level is changed until the returned function is called
+func TestLogger_WithTemporaryLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	func() {
+		defer logger.WithTemporaryLevel(log.DEBUG)()
+		logger.Debug("Inside debug message")
+	}()
+	if !containsLogMessage(buf.String(), "DEBUG", "Inside debug message") {
+		t.Errorf("Expected debug message inside the scope, got %v", buf.String())
+	}
+
+	buf.Reset()
+	logger.Debug("Outside debug message")
+	if buf.String() != "" {
+		t.Errorf("Expected level to be restored after the scope, got %v", buf.String())
+	}
+}
+
+// TestLogger_WithTemporaryLevelConcurrent verifies that level changes are safe alongside concurrent logging
+func TestLogger_WithTemporaryLevelConcurrent(t *testing.T) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.WithTemporaryLevel(log.DEBUG)()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Debug("Concurrent message")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 // TestLogger_JsonLogMessage verifies that the logger correctly logs messages in JSON format
 func TestLogger_JsonLogMessage(t *testing.T) {
 	var buf bytes.Buffer
@@ -95,6 +661,242 @@ func TestLogger_JsonLogMessage(t *testing.T) {
 	}
 }
 
+// TestJSONFormatter_NDJSON verifies that messages with control characters produce exactly one JSON object per line
+func TestJSONFormatter_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	messages := []string{"line one\nline two", `say "hi"`, "tab\tseparated", "bell\a and nul\x00"}
+	for _, message := range messages {
+		logger.Info(message)
+	}
+	// A field that json.Marshal rejects forces the fallback path
+	formatter := &log.JSONFormatter{}
+	buf.WriteString(formatter.FormatEntry(log.Entry{
+		Level:   log.ERROR,
+		Message: "fallback\n\"quoted\"\t",
+		Fields:  []log.Field{{Key: "ch", Value: make(chan int)}},
+	}) + "\n")
+	messages = append(messages, "fallback\n\"quoted\"\t")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(messages) {
+		t.Fatalf("Expected %d lines, got %d: %q", len(messages), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Errorf("Expected line %d to be a JSON object, got error %v for %q", i, err, line)
+			continue
+		}
+		if entry["message"] != messages[i] {
+			t.Errorf("Expected message %q, got %q", messages[i], entry["message"])
+		}
+	}
+}
+
+// TestJSONFormatter_OmitEmpty verifies that no file or line keys are written when the caller is disabled or unknown
+func TestJSONFormatter_OmitEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{DisableCaller: true})
+	logger.Info("Caller disabled")
+
+	unknown := (&log.JSONFormatter{OmitEmpty: true}).FormatEntry(log.Entry{Level: log.INFO, Message: "Caller unknown"})
+	for _, output := range []string{buf.String(), unknown} {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &entry); err != nil {
+			t.Fatalf("Expected valid JSON log message, got error %v", err)
+		}
+		if _, ok := entry["file"]; ok {
+			t.Errorf("Expected no file key, got %s", output)
+		}
+		if _, ok := entry["line"]; ok {
+			t.Errorf("Expected no line key, got %s", output)
+		}
+	}
+	if unknown != `{"level":"INFO","message":"Caller unknown"}` {
+		t.Errorf("Expected the zero timestamp to be omitted, got %s", unknown)
+	}
+}
+
+// TestJSONFormatter_NestedCaller verifies that NestedCaller groups the caller under one object
+func TestJSONFormatter_NestedCaller(t *testing.T) {
+	formatter := &log.JSONFormatter{NestedCaller: true, UTC: true}
+	output := formatter.FormatEntry(log.Entry{
+		Time:     time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Level:    log.INFO,
+		File:     "/src/app/main.go",
+		Line:     42,
+		Function: "main.main",
+		Message:  "Started",
+		Fields:   []log.Field{{Key: "file", Value: "config.yaml"}, {Key: "caller", Value: "clash"}},
+	})
+
+	expected := `{"timestamp":"2024-03-01T12:30:00Z","level":"INFO","message":"Started",` +
+		`"caller":{"file":"main.go","line":42,"function":"main.main"},"file":"config.yaml","fields.caller":"clash"}`
+	if output != expected {
+		t.Errorf("Expected %s, got %s", expected, output)
+	}
+}
+
+// TestJSONFormatter_Schema verifies the keys and values of JSON output, including clashing fields
+func TestJSONFormatter_Schema(t *testing.T) {
+	formatter := &log.JSONFormatter{UTC: true, NumericLevel: true}
+	output := formatter.FormatEntry(log.Entry{
+		Time:    time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Level:   log.WARN,
+		File:    "/src/app/main.go",
+		Line:    42,
+		Message: "Disk almost full",
+		Fields:  []log.Field{{Key: "free", Value: 0.05}, {Key: "line", Value: "clash"}, {Key: "tags", Value: []string{"disk"}}},
+	})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v for %q", err, output)
+	}
+	expected := map[string]interface{}{
+		"timestamp":   "2024-03-01T12:30:00Z",
+		"level":       "WARN",
+		"level_num":   float64(40),
+		"message":     "Disk almost full",
+		"file":        "main.go",
+		"line":        float64(42),
+		"free":        0.05,
+		"fields.line": "clash",
+		"tags":        []interface{}{"disk"},
+	}
+	if !reflect.DeepEqual(entry, expected) {
+		t.Errorf("Expected %v, got %v", expected, entry)
+	}
+	if !strings.HasPrefix(output, `{"timestamp":"2024-03-01T12:30:00Z","level":"WARN","level_num":40,"message":"Disk almost full","file":"main.go","line":42,"free":0.05`) {
+		t.Errorf("Expected standard keys first and fields in the order added, got %s", output)
+	}
+}
+
+// TestJSONFormatter_FieldTypes verifies that field values keep their JSON types through the logger
+func TestJSONFormatter_FieldTypes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{DisableCaller: true})
+	type limits struct {
+		Max int `json:"max"`
+	}
+
+	logger.WithField("count", 42).WithFields(log.Fields{
+		"ok":     true,
+		"ratio":  0.5,
+		"id":     int64(9007199254740993),
+		"nested": map[string]interface{}{"retries": 3, "final": false},
+		"limits": limits{Max: 10},
+		"none":   nil,
+	}).Info("Typed fields")
+
+	output := buf.String()
+	for _, expected := range []string{
+		`"count":42`, `"ok":true`, `"ratio":0.5`, `"id":9007199254740993`,
+		`"nested":{"final":false,"retries":3}`, `"limits":{"max":10}`, `"none":null`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s in the output, got %s", expected, output)
+		}
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if _, ok := entry["count"].(float64); !ok {
+		t.Errorf("Expected count to be a JSON number, got %T", entry["count"])
+	}
+	if _, ok := entry["ok"].(bool); !ok {
+		t.Errorf("Expected ok to be a JSON boolean, got %T", entry["ok"])
+	}
+}
+
+// TestJSONFormatter_NumericLevelKey verifies that the level is written as text and as a number under the configured key
+func TestJSONFormatter_NumericLevelKey(t *testing.T) {
+	tests := []struct {
+		scheme   log.LevelScheme
+		level    log.LogLevel
+		expected string
+	}{
+		{log.SyslogLevels, log.ERROR, `"level":"ERROR","level_value":3,`},
+		{log.SyslogLevels, log.DEBUG, `"level":"DEBUG","level_value":7,`},
+		{log.OrdinalLevels, log.DEBUG, `"level":"DEBUG","level_value":0,`},
+		{log.OrdinalLevels, log.WARN, `"level":"WARN","level_value":3,`},
+	}
+	for _, tt := range tests {
+		formatter := &log.JSONFormatter{NumericLevel: true, NumericLevelKey: "level_value", LevelScheme: tt.scheme}
+		output := formatter.FormatEntry(log.Entry{
+			Level:   tt.level,
+			Message: "Levels",
+			Fields:  []log.Field{{Key: "level_value", Value: "clash"}, {Key: "level_num", Value: 1}},
+		})
+		if !strings.Contains(output, tt.expected) {
+			t.Errorf("Expected %s in the output, got %s", tt.expected, output)
+		}
+		if !strings.HasSuffix(output, `"fields.level_value":"clash","level_num":1}`) {
+			t.Errorf("Expected clashing fields to be renamed and others kept, got %s", output)
+		}
+	}
+
+	output := (&log.JSONFormatter{}).FormatEntry(log.Entry{Level: log.INFO, Message: "Text only"})
+	if strings.Contains(output, "level_num") || strings.Contains(output, "level_value") {
+		t.Errorf("Expected only the level name by default, got %s", output)
+	}
+}
+
+// jsonKeys returns the top-level keys of a JSON object in the order they appear, duplicates included
+func jsonKeys(t *testing.T, output string) []string {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(output))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		t.Fatalf("Expected a JSON object, got %s", output)
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			t.Fatalf("Expected valid JSON, got error %v: %s", err, output)
+		}
+		keys = append(keys, token.(string))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatalf("Expected valid JSON, got error %v: %s", err, output)
+		}
+	}
+	return keys
+}
+
+// TestJSONFormatter_UniqueKeys verifies that no combination of NumericLevelKey and fields writes a key twice
+func TestJSONFormatter_UniqueKeys(t *testing.T) {
+	tests := []struct {
+		formatter *log.JSONFormatter
+		expected  []string
+		contains  string
+	}{
+		{&log.JSONFormatter{DisableCaller: true, NumericLevel: true, NumericLevelKey: "level"},
+			[]string{"timestamp", "level", "message", "fields.level", "fields.fields.level"}, `"level":30,`},
+		{&log.JSONFormatter{DisableCaller: true, NumericLevel: true, NumericLevelKey: "message"},
+			[]string{"timestamp", "level", "level_num", "message", "fields.level", "fields.fields.level"}, `"level_num":30,`},
+		{&log.JSONFormatter{DisableCaller: true},
+			[]string{"timestamp", "level", "message", "fields.level", "fields.fields.level"}, `"level":"INFO",`},
+	}
+	for _, tt := range tests {
+		output := tt.formatter.FormatEntry(log.Entry{
+			Level:   log.INFO,
+			Message: "Unique keys",
+			Fields:  []log.Field{{Key: "fields.level", Value: "first"}, {Key: "level", Value: "second"}},
+		})
+		if keys := jsonKeys(t, output); !reflect.DeepEqual(keys, tt.expected) {
+			t.Errorf("Expected keys %v, got %v", tt.expected, keys)
+		}
+		if !strings.Contains(output, tt.contains) {
+			t.Errorf("Expected %s in the output, got %s", tt.contains, output)
+		}
+	}
+}
+
 // TestLogger_CustomFormatter verifies that the logger correctly logs messages using a custom formatter
 func TestLogger_CustomFormatter(t *testing.T) {
 	var buf bytes.Buffer
@@ -108,6 +910,121 @@ func TestLogger_CustomFormatter(t *testing.T) {
 	}
 }
 
+// TestLogger_SetLevelFormatter verifies that a per-level formatter is used only for its level
+func TestLogger_SetLevelFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &MyCustomFormatter{})
+	logger.SetLevelFormatter(log.ERROR, &log.JSONFormatter{})
+
+	logger.Info("Compact message")
+	logger.Error("Verbose message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	if lines[0] != "**CUSTOM LOG** [INFO] Compact message" {
+		t.Errorf("Expected INFO to use the default formatter, got %q", lines[0])
+	}
+	if !isValidJSON(lines[1]) || !strings.Contains(lines[1], "Verbose message") {
+		t.Errorf("Expected ERROR to use the JSON formatter, got %q", lines[1])
+	}
+}
+
+// panicStringer panics in String with a value that itself panics when printed
+type panicStringer struct{}
+
+func (panicStringer) String() string {
+	panic(panicStringer{})
+}
+
+// TestLogger_PanickingStringer verifies that a value whose String method panics does not crash the program
+func TestLogger_PanickingStringer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.Info("Value:", panicStringer{})
+	logger.Info("Next message")
+
+	expected := "[INFO] (PANIC formatting log message: log_test.panicStringer)\n"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected the placeholder line %q, got %q", expected, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Next message") {
+		t.Errorf("Expected logging to continue after the panic, got %q", buf.String())
+	}
+}
+
+// TestLogger_MultiArgMatchesSprint verifies that multi-argument messages are spaced like fmt.Sprint
+func TestLogger_MultiArgMatchesSprint(t *testing.T) {
+	type name string
+	args := []interface{}{"count", 1, 2, "x", name("y"), 3, nil, errors.New("e"), 4.5}
+
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &MyCustomFormatter{})
+	logger.SetInitialBufferSize(1)
+	logger.Info(args...)
+
+	expected := "**CUSTOM LOG** [INFO] " + fmt.Sprint(args...) + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+// TestLogger_SetMaxMessageBytes verifies truncation under, at and over the limit, including multibyte characters
+func TestLogger_SetMaxMessageBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{"under", "short", "short"},
+		{"at", "exactly10!", "exactly10!"},
+		{"over", "this is far too long", "this is fa" + log.TruncatedMarker},
+		{"multibyte", "12345678é€", "12345678é" + log.TruncatedMarker},
+		{"split rune", "123456789€", "123456789" + log.TruncatedMarker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.NewLogger(&buf, log.INFO, &bareFormatter{})
+			logger.SetMaxMessageBytes(10)
+
+			logger.Info(tt.message)
+
+			expected := "[INFO] " + tt.expected + "\n"
+			if buf.String() != expected {
+				t.Errorf("Expected %q, got %q", expected, buf.String())
+			}
+			if !utf8.ValidString(buf.String()) {
+				t.Errorf("Expected valid UTF-8, got %q", buf.String())
+			}
+		})
+	}
+}
+
+// TestLogger_SetMaxFields verifies that fields beyond the limit are dropped and counted in a marker
+func TestLogger_SetMaxFields(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetMaxFields(3)
+	fields := log.Fields{}
+	for i := 0; i < 1000; i++ {
+		fields[fmt.Sprintf("key%03d", i)] = i
+	}
+
+	logger.WithFields(fields).Info("Too many fields")
+	logger.WithFields(log.Fields{"a": 1, "b": 2, "c": 3}).Info("At the limit")
+
+	entries := logger.Entries()
+	expected := log.Fields{"key000": 0, "key001": 1, "key002": 2, "_fields_truncated": 997}
+	if !reflect.DeepEqual(entries[0].Fields, expected) {
+		t.Errorf("Expected %v, got %v", expected, entries[0].Fields)
+	}
+	if _, ok := entries[1].Fields["_fields_truncated"]; ok || len(entries[1].Fields) != 3 {
+		t.Errorf("Expected the entry at the limit to be kept whole, got %v", entries[1].Fields)
+	}
+}
+
 // MyCustomFormatter is a test custom formatter
 type MyCustomFormatter struct{}
 
@@ -132,6 +1049,8 @@ func logLevelToString(level log.LogLevel) string {
 		return "DEBUG"
 	case log.INFO:
 		return "INFO"
+	case log.NOTICE:
+		return "NOTICE"
 	case log.WARN:
 		return "WARN"
 	case log.ERROR:
@@ -146,3 +1065,171 @@ func logLevelToString(level log.LogLevel) string {
 func TestMain(m *testing.M) {
 	m.Run()
 }
+
+// TestDefaultFormatter_DisableCaller verifies that no file:line is present when caller lookup is disabled
+func TestDefaultFormatter_DisableCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.Info("Info message")
+
+	if strings.Contains(buf.String(), ".go:") {
+		t.Errorf("Expected no caller information in output, got %v", buf.String())
+	}
+	if !containsLogMessage(buf.String(), "INFO", "Info message") {
+		t.Errorf("Expected 'INFO - Info message' in output, got %v", buf.String())
+	}
+}
+
+// TestDefaultFormatter_Caller verifies that file:line points at the line that issued the log call
+func TestDefaultFormatter_Caller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("Info message")
+
+	expected := fmt.Sprintf("logger_test.go:%d", line+1)
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Expected '%s' in output, got %v", expected, buf.String())
+	}
+}
+
+// TestJSONFormatter_CallerLine verifies that the file and line fields point at the line that issued the log call
+func TestJSONFormatter_CallerLine(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("Info message")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["file"] != "logger_test.go" || entry["line"] != float64(line+1) {
+		t.Errorf("Expected file logger_test.go and line %d, got %v:%v", line+1, entry["file"], entry["line"])
+	}
+}
+
+// BenchmarkLogger_Caller measures logging with the caller lookup enabled
+func BenchmarkLogger_Caller(b *testing.B) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{})
+	for i := 0; i < b.N; i++ {
+		logger.Info("Benchmark message")
+	}
+}
+
+// BenchmarkLogger_NoCaller measures logging with the caller lookup disabled
+func BenchmarkLogger_NoCaller(b *testing.B) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	for i := 0; i < b.N; i++ {
+		logger.Info("Benchmark message")
+	}
+}
+
+// nopFormatter is a formatter that produces no output, used to isolate message assembly in benchmarks
+type nopFormatter struct{}
+
+func (f *nopFormatter) Format(level log.LogLevel, message string) string {
+	return ""
+}
+
+// BenchmarkLogger_SingleString measures logging a single string argument
+func BenchmarkLogger_SingleString(b *testing.B) {
+	logger := log.NewLogger(io.Discard, log.INFO, &nopFormatter{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("Benchmark message")
+	}
+}
+
+// BenchmarkLogger_MultiArg measures logging several arguments of mixed types
+func BenchmarkLogger_MultiArg(b *testing.B) {
+	logger := log.NewLogger(io.Discard, log.INFO, &nopFormatter{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("Benchmark message ", i, " of ", b.N)
+	}
+}
+
+// BenchmarkLogger_LargeMessage measures assembling a large multi-argument message with
+// the default buffer size and with a buffer size hint matching the message
+func BenchmarkLogger_LargeMessage(b *testing.B) {
+	chunk := strings.Repeat("x", 1024)
+	args := make([]interface{}, 96)
+	for i := range args {
+		args[i] = chunk
+	}
+	for _, size := range []int{log.DefaultInitialBufferSize, 128 << 10} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			logger := log.NewLogger(io.Discard, log.INFO, &nopFormatter{})
+			logger.SetInitialBufferSize(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Info(args...)
+			}
+		})
+	}
+}
+
+// BenchmarkJSONFormatter measures formatting an entry with a few structured fields as JSON
+func BenchmarkJSONFormatter(b *testing.B) {
+	formatter := &log.JSONFormatter{}
+	entry := log.Entry{
+		Time:    time.Now(),
+		Level:   log.INFO,
+		File:    "/src/app/main.go",
+		Line:    42,
+		Message: "Request served",
+		Fields:  []log.Field{{Key: "method", Value: "GET"}, {Key: "status", Value: 200}, {Key: "path", Value: "/index"}},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		formatter.FormatEntry(entry)
+	}
+}
+
+// badMarshaler produces invalid JSON from MarshalJSON
+type badMarshaler struct{}
+
+func (badMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"unterminated`), nil
+}
+
+// TestJSONFormatter_BadMarshaler verifies that a field marshalling to invalid JSON still yields a valid error object
+func TestJSONFormatter_BadMarshaler(t *testing.T) {
+	formatter := &log.JSONFormatter{}
+	output := formatter.FormatEntry(log.Entry{Level: log.INFO, Message: "bad\nfield", Fields: []log.Field{{Key: "bad", Value: badMarshaler{}}}})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got error %v for %q", err, output)
+	}
+	if entry["message"] != "bad\nfield" || entry["error"] == nil {
+		t.Errorf("Expected the message and an error key, got %v", entry)
+	}
+}
+
+// FuzzJSONFormatter verifies that any message and field produce exactly one valid JSON object on one line
+func FuzzJSONFormatter(f *testing.F) {
+	f.Add("plain message", "key", "value")
+	f.Add("line one\nline two", "quote\"d", "tab\there")
+	f.Add("\x00\x1f\x7f", " ", " ")
+	f.Add("invalid \xff\xfe utf-8", "\xc3", "\xed\xa0\x80")
+	f.Add(`{"nested":"json"}`, "level", "<script>&</script>")
+	f.Fuzz(func(t *testing.T, message, key, value string) {
+		formatter := &log.JSONFormatter{}
+		for _, output := range []string{
+			formatter.Format(log.ERROR, message),
+			formatter.FormatEntry(log.Entry{Level: log.INFO, Message: message, Fields: []log.Field{{Key: key, Value: value}}}),
+		} {
+			if !json.Valid([]byte(output)) {
+				t.Fatalf("Expected valid JSON, got %q", output)
+			}
+			if strings.Contains(output, "\n") {
+				t.Fatalf("Expected a single line, got %q", output)
+			}
+		}
+	})
+}
@@ -0,0 +1,20 @@
+module github.com/pod32g/simple-logger/loggrpc
+
+go 1.22.3
+
+require (
+	github.com/pod32g/simple-logger v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.66.0
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/pod32g/simple-logger => ../
@@ -0,0 +1,60 @@
+// Package loggrpc provides gRPC server interceptors that log each call.
+// It lives in its own module so that users of the core logger do not
+// depend on gRPC.
+package loggrpc
+
+import (
+	"context"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor that logs the method, status code,
+// and duration of each unary call. Successful calls are logged at INFO and failed
+// calls at ERROR.
+func UnaryServerInterceptor(l *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(l, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that logs the method, status code,
+// and duration of each streaming call. Successful calls are logged at INFO and
+// failed calls at ERROR.
+func StreamServerInterceptor(l *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(l, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// logCall writes the entry for a finished call
+func logCall(l *log.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	entry := l.WithFields(log.Fields{
+		"method":   method,
+		"code":     code.String(),
+		"duration": time.Since(start),
+	})
+	if err != nil {
+		entry = entry.WithField("error", err.Error())
+	}
+	entry.Log(levelForCode(code), "finished call ", method)
+}
+
+// levelForCode maps a gRPC status code to the level its call is logged at
+func levelForCode(code codes.Code) log.LogLevel {
+	if code == codes.OK {
+		return log.INFO
+	}
+	return log.ERROR
+}
@@ -0,0 +1,82 @@
+package loggrpc_test
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/loggrpc"
+	"github.com/pod32g/simple-logger/logtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnaryServerInterceptor_OK verifies that a successful call is logged at INFO
+func TestUnaryServerInterceptor_OK(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	interceptor := loggrpc.UnaryServerInterceptor(logger.Logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	resp, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	})
+
+	if err != nil || resp != "resp" {
+		t.Fatalf("Expected handler response to pass through, got %v, %v", resp, err)
+	}
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Level != log.INFO {
+		t.Errorf("Expected level INFO, got %v", entries[0].Level)
+	}
+	if entries[0].Fields["method"] != "/test.Service/Get" || entries[0].Fields["code"] != "OK" {
+		t.Errorf("Expected method and OK code fields, got %v", entries[0].Fields)
+	}
+	if _, ok := entries[0].Fields["duration"]; !ok {
+		t.Errorf("Expected a duration field, got %v", entries[0].Fields)
+	}
+}
+
+// TestUnaryServerInterceptor_Error verifies that a failed call is logged at ERROR with its code
+func TestUnaryServerInterceptor_Error(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	interceptor := loggrpc.UnaryServerInterceptor(logger.Logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	_, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected handler error to pass through, got %v", err)
+	}
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Level != log.ERROR {
+		t.Errorf("Expected level ERROR, got %v", entries[0].Level)
+	}
+	if entries[0].Fields["code"] != "NotFound" {
+		t.Errorf("Expected code NotFound, got %v", entries[0].Fields["code"])
+	}
+}
+
+// TestStreamServerInterceptor verifies that streaming calls are logged with their code
+func TestStreamServerInterceptor(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	interceptor := loggrpc.StreamServerInterceptor(logger.Logger)
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch"}
+
+	interceptor(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return status.Error(codes.Unavailable, "down")
+	})
+
+	logger.AssertLogged(t, log.ERROR, "/test.Service/Watch")
+	if entries := logger.Entries(); len(entries) != 1 || entries[0].Fields["code"] != "Unavailable" {
+		t.Errorf("Expected one entry with code Unavailable, got %v", entries)
+	}
+}
@@ -0,0 +1,134 @@
+// Package logjournald writes log entries to the systemd journal using its native
+// protocol, so that fields can be queried with journalctl. Importing the package
+// registers the "journald" output for LoggerConfig.Output:
+//
+//	import _ "github.com/pod32g/simple-logger/logjournald"
+//
+// It lives in its own package so that users of the core logger who do not run
+// under systemd do not carry it.
+package logjournald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// SocketPath is the journald socket used by NewJournaldWriter and the "journald" output
+var SocketPath = "/run/systemd/journal/socket"
+
+func init() {
+	log.RegisterOutput("journald", func(config log.LoggerConfig) (io.Writer, error) {
+		return NewJournaldWriter()
+	})
+}
+
+// JournaldWriter sends entries to journald. Each entry becomes one journal record with
+// MESSAGE, PRIORITY (the syslog severity of its level), SYSLOG_IDENTIFIER, the caller's
+// CODE_FILE, CODE_LINE and CODE_FUNC when known, and one field per structured field
+// with its key upper-cased.
+type JournaldWriter struct {
+	conn       net.Conn
+	identifier string
+}
+
+// NewJournaldWriter connects to the journald socket at SocketPath. It returns an error
+// if the socket does not exist, for example when not running under systemd.
+func NewJournaldWriter() (*JournaldWriter, error) {
+	return NewJournaldWriterAt(SocketPath)
+}
+
+// NewJournaldWriterAt connects to the journald socket at path
+func NewJournaldWriterAt(path string) (*JournaldWriter, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("journald socket not available: %w", err)
+	}
+	conn, err := net.Dial("unixgram", path)
+	if err != nil {
+		return nil, err
+	}
+	return &JournaldWriter{conn: conn, identifier: filepath.Base(os.Args[0])}, nil
+}
+
+// Write sends p as the MESSAGE of a record at INFO priority, for use as a plain io.Writer
+func (w *JournaldWriter) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+	w.writeHeader(&buf, strings.TrimSuffix(string(p), "\n"), log.INFO)
+	if _, err := w.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteEntry sends the entry as a journal record with its structured fields
+func (w *JournaldWriter) WriteEntry(e log.Entry, formatted string) error {
+	var buf bytes.Buffer
+	w.writeHeader(&buf, e.Message, e.Level)
+	if e.File != "" {
+		writeField(&buf, "CODE_FILE", e.File)
+		writeField(&buf, "CODE_LINE", fmt.Sprint(e.Line))
+	}
+	if e.Function != "" {
+		writeField(&buf, "CODE_FUNC", e.Function)
+	}
+	for _, field := range e.Fields {
+		if name := fieldName(field.Key); name != "" {
+			writeField(&buf, name, fmt.Sprint(field.Value))
+		}
+	}
+	_, err := w.conn.Write(buf.Bytes())
+	return err
+}
+
+// Close closes the connection to journald
+func (w *JournaldWriter) Close() error {
+	return w.conn.Close()
+}
+
+// writeHeader writes the fields every record carries
+func (w *JournaldWriter) writeHeader(buf *bytes.Buffer, message string, level log.LogLevel) {
+	writeField(buf, "MESSAGE", message)
+	writeField(buf, "PRIORITY", fmt.Sprint(log.SyslogLevels.Value(level)))
+	writeField(buf, "SYSLOG_IDENTIFIER", w.identifier)
+}
+
+// writeField appends one field in the native protocol framing: NAME=value followed by a
+// newline, or, for values containing a newline, NAME, a newline, the value's length as
+// a little-endian 64-bit integer, the value and a newline
+func writeField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// fieldName converts a field key to a journal field name: upper case letters, digits
+// and underscores, not starting with an underscore or digit, which journald reserves
+// or rejects. It returns "" if nothing usable remains.
+func fieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	return strings.TrimLeft(name, "_0123456789")
+}
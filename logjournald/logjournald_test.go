@@ -0,0 +1,128 @@
+package logjournald_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logjournald"
+)
+
+// listenJournal creates a fake journald socket and points SocketPath at it
+func listenJournal(t *testing.T) net.PacketConn {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("unix datagram sockets are not available on windows")
+	}
+	// Socket paths are limited to about 100 bytes, so avoid the long t.TempDir paths
+	dir, err := os.MkdirTemp("", "journal")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "socket")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatalf("Failed to listen on fake journal socket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	previous := logjournald.SocketPath
+	logjournald.SocketPath = path
+	t.Cleanup(func() { logjournald.SocketPath = previous })
+	return conn
+}
+
+// readRecord receives one datagram and parses its native protocol fields
+func readRecord(t *testing.T, conn net.PacketConn) map[string]string {
+	t.Helper()
+	buf := make([]byte, 64<<10)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read journal record: %v", err)
+	}
+	data := buf[:n]
+
+	fields := map[string]string{}
+	for len(data) > 0 {
+		newline := bytes.IndexByte(data, '\n')
+		if newline < 0 {
+			t.Fatalf("Expected every field to end with a newline, got %q", data)
+		}
+		line := data[:newline]
+		if eq := bytes.IndexByte(line, '='); eq >= 0 {
+			fields[string(line[:eq])] = string(line[eq+1:])
+			data = data[newline+1:]
+			continue
+		}
+		// Binary framing: name, newline, 64-bit little-endian length, value, newline
+		name := string(line)
+		data = data[newline+1:]
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[name] = string(data[8 : 8+size])
+		if data[8+size] != '\n' {
+			t.Fatalf("Expected a newline after the value of %s", name)
+		}
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+// TestJournaldOutput verifies that entries logged through the "journald" output carry priority and fields
+func TestJournaldOutput(t *testing.T) {
+	conn := listenJournal(t)
+	logger := log.ApplyConfig(log.LoggerConfig{Level: log.INFO, Output: "journald", Format: "text", EnableCaller: true})
+	defer logger.Shutdown(context.Background())
+
+	logger.WithFields(log.Fields{"request_id": "r-1", "trace": "line one\nline two"}).Warn("Slow request")
+
+	fields := readRecord(t, conn)
+	expected := map[string]string{
+		"MESSAGE":    "Slow request",
+		"PRIORITY":   "4",
+		"REQUEST_ID": "r-1",
+		"TRACE":      "line one\nline two",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, fields[name])
+		}
+	}
+	if filepath.Base(fields["CODE_FILE"]) != "logjournald_test.go" {
+		t.Errorf("Expected CODE_FILE to be the test file, got %q", fields["CODE_FILE"])
+	}
+	if fields["SYSLOG_IDENTIFIER"] == "" {
+		t.Errorf("Expected a SYSLOG_IDENTIFIER, got %v", fields)
+	}
+}
+
+// TestJournaldWriter_Write verifies that plain writes are sent as INFO messages
+func TestJournaldWriter_Write(t *testing.T) {
+	conn := listenJournal(t)
+	w, err := logjournald.NewJournaldWriter()
+	if err != nil {
+		t.Fatalf("Failed to connect to fake journal: %v", err)
+	}
+	defer w.Close()
+
+	w.Write([]byte("plain text\n"))
+
+	fields := readRecord(t, conn)
+	if fields["MESSAGE"] != "plain text" || fields["PRIORITY"] != "6" {
+		t.Errorf("Expected MESSAGE 'plain text' at priority 6, got %v", fields)
+	}
+}
+
+// TestNewJournaldWriter_NoSocket verifies that a missing socket is reported as an error
+func TestNewJournaldWriter_NoSocket(t *testing.T) {
+	if _, err := logjournald.NewJournaldWriterAt(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("Expected an error when the journal socket does not exist")
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/pod32g/simple-logger/loglogr
+
+go 1.22.3
+
+require (
+	github.com/go-logr/logr v1.4.2
+	github.com/pod32g/simple-logger v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/pod32g/simple-logger => ../
@@ -0,0 +1,107 @@
+// Package loglogr adapts the logger to go-logr/logr, so that libraries and Kubernetes
+// controllers written against logr.Logger write through the logger's outputs:
+//
+//	logger := logr.New(loglogr.NewLogSink(log.Default()))
+//
+// It lives in its own module so that users of the core logger do not depend on logr.
+package loglogr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	log "github.com/pod32g/simple-logger"
+)
+
+func init() {
+	// Report the code calling logr.Logger as the caller, not logr or this adapter
+	log.SkipCallerPackage("github.com/go-logr/logr")
+	log.SkipCallerPackage("github.com/pod32g/simple-logger/loglogr")
+}
+
+// LogSink is a logr.LogSink backed by a Logger. Verbosity 0 is logged at INFO and any
+// higher verbosity at DEBUG; errors are logged at ERROR with the error in the "error"
+// field. Names given with WithName are joined with dots in the "logger" field, as with
+// Logger.Named, and key/value pairs become structured fields.
+type LogSink struct {
+	logger *log.Logger
+}
+
+var _ logr.LogSink = (*LogSink)(nil)
+
+// NewLogSink returns a LogSink writing to logger
+func NewLogSink(logger *log.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// Init is called by logr.New; caller lookup does not need its call depth
+func (s *LogSink) Init(info logr.RuntimeInfo) {}
+
+// Enabled reports whether messages at the given verbosity are logged
+func (s *LogSink) Enabled(level int) bool {
+	return s.logger.IsLevelEnabled(levelFor(level))
+}
+
+// Info logs msg at the level matching the verbosity, with keysAndValues as fields
+func (s *LogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if levelFor(level) == log.DEBUG {
+		s.logger.Debugw(msg, keysAndValues...)
+		return
+	}
+	s.logger.Infow(msg, keysAndValues...)
+}
+
+// Error logs msg at ERROR with err in the "error" field and keysAndValues as fields
+func (s *LogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if err != nil {
+		keysAndValues = append([]interface{}{"error", err.Error()}, keysAndValues...)
+	}
+	s.logger.Errorw(msg, keysAndValues...)
+}
+
+// WithValues returns a LogSink that adds keysAndValues to every entry
+func (s *LogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &LogSink{logger: s.logger.WithDefaults(fields(keysAndValues))}
+}
+
+// WithName returns a LogSink whose entries carry name appended to the current name
+func (s *LogSink) WithName(name string) logr.LogSink {
+	return &LogSink{logger: s.logger.Named(name)}
+}
+
+// levelFor maps a logr verbosity to a log level
+func levelFor(verbosity int) log.LogLevel {
+	if verbosity > 0 {
+		return log.DEBUG
+	}
+	return log.INFO
+}
+
+// fieldErrorKey is the field in which the core logger reports malformed fields
+const fieldErrorKey = "_field_error"
+
+// fields turns alternating keys and values into Fields. As with Logger.Infow, a
+// trailing key without a value gets the value "(MISSING)"; keys that are not strings
+// are converted with fmt.Sprint. Both are reported in the _field_error field.
+func fields(keysAndValues []interface{}) log.Fields {
+	fields := make(log.Fields, (len(keysAndValues)+1)/2)
+	var problems []string
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+			problems = append(problems, fmt.Sprintf("%s: non-string key of type %T", key, keysAndValues[i]))
+		}
+		if i+1 == len(keysAndValues) {
+			fields[key] = "(MISSING)"
+			problems = append(problems, fmt.Sprintf("%s: missing value", key))
+			break
+		}
+		fields[key] = keysAndValues[i+1]
+	}
+	if len(problems) > 0 {
+		fields[fieldErrorKey] = strings.Join(problems, "; ")
+	}
+	return fields
+}
@@ -0,0 +1,88 @@
+package loglogr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/loglogr"
+)
+
+// decodeLines parses each line of JSON output
+func decodeLines(t *testing.T, output string) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Expected valid JSON log message, got error %v for %q", err, line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestLogSink verifies that names, values, verbosity and errors are mapped onto the logger
+func TestLogSink(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logr.New(loglogr.NewLogSink(log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})))
+	reconciler := logger.WithName("controller").WithName("reconciler").WithValues("namespace", "default")
+
+	_, _, line, _ := runtime.Caller(0)
+	reconciler.Info("Reconciled", "name", "web")
+	reconciler.V(1).Info("Verbose detail")
+	reconciler.Error(errors.New("conflict"), "Update failed", "name", "db")
+
+	entries := decodeLines(t, buf.String())
+	if len(entries) != 2 {
+		t.Fatalf("Expected the V(1) message to be filtered at INFO, got %d entries", len(entries))
+	}
+	info := entries[0]
+	if info["level"] != "INFO" || info["message"] != "Reconciled" || info["logger"] != "controller.reconciler" || info["namespace"] != "default" || info["name"] != "web" {
+		t.Errorf("Expected an INFO entry with the composed name and values, got %v", info)
+	}
+	if info["file"] != "loglogr_test.go" || info["line"] != float64(line+1) {
+		t.Errorf("Expected the caller to be the test, got %v:%v", info["file"], info["line"])
+	}
+	failed := entries[1]
+	if failed["level"] != "ERROR" || failed["error"] != "conflict" || failed["name"] != "db" || failed["logger"] != "controller.reconciler" {
+		t.Errorf("Expected an ERROR entry with the error and values, got %v", failed)
+	}
+}
+
+// TestLogSink_Verbosity verifies that V-levels above 0 are logged at DEBUG
+func TestLogSink_Verbosity(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logr.New(loglogr.NewLogSink(log.NewLogger(&buf, log.DEBUG, &log.JSONFormatter{DisableCaller: true})))
+
+	if !logger.V(2).Enabled() {
+		t.Errorf("Expected V(2) to be enabled at DEBUG")
+	}
+	logger.V(2).Info("Very verbose")
+
+	if entry := decodeLines(t, buf.String())[0]; entry["level"] != "DEBUG" || entry["message"] != "Very verbose" {
+		t.Errorf("Expected a DEBUG entry, got %v", entry)
+	}
+}
+
+// TestLogSink_MalformedValues verifies that a non-string key and a trailing key without a value are reported, not dropped
+func TestLogSink_MalformedValues(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logr.New(loglogr.NewLogSink(log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})))
+
+	logger.WithValues(42, "answer", "dangling").Info("Malformed values")
+
+	entry := decodeLines(t, buf.String())[0]
+	if entry["42"] != "answer" || entry["dangling"] != "(MISSING)" {
+		t.Errorf("Expected both keys to be kept, got %v", entry)
+	}
+	fieldError, _ := entry["_field_error"].(string)
+	if !strings.Contains(fieldError, "42: non-string key of type int") || !strings.Contains(fieldError, "dangling: missing value") {
+		t.Errorf("Expected both problems in _field_error, got %q", fieldError)
+	}
+}
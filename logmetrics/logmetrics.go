@@ -0,0 +1,48 @@
+// Package logmetrics exposes Prometheus counters of log messages by level.
+// It lives in its own module so that users of the core logger do not
+// depend on the Prometheus client.
+package logmetrics
+
+import (
+	"strings"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsHook is a log.Hook and prometheus.Collector that counts log messages by level
+type MetricsHook struct {
+	messages *prometheus.CounterVec
+}
+
+// NewMetricsHook creates a MetricsHook. Register it with Logger.AddHook to count
+// messages and with a prometheus.Registerer to expose the counts.
+func NewMetricsHook() *MetricsHook {
+	return &MetricsHook{
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "log_messages_total",
+			Help: "Number of log messages written, by level.",
+		}, []string{"level"}),
+	}
+}
+
+// Levels reports that the hook counts messages at every level
+func (h *MetricsHook) Levels() []log.LogLevel {
+	return log.AllLevels()
+}
+
+// Fire increments the counter for the entry's level
+func (h *MetricsHook) Fire(e log.Entry) error {
+	h.messages.WithLabelValues(strings.ToLower(e.Level.String())).Inc()
+	return nil
+}
+
+// Describe implements prometheus.Collector
+func (h *MetricsHook) Describe(ch chan<- *prometheus.Desc) {
+	h.messages.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (h *MetricsHook) Collect(ch chan<- prometheus.Metric) {
+	h.messages.Collect(ch)
+}
@@ -0,0 +1,49 @@
+package logmetrics_test
+
+import (
+	"io"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logmetrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestMetricsHook verifies that log messages are counted by level
+func TestMetricsHook(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	hook := logmetrics.NewMetricsHook()
+	registry.MustRegister(hook)
+
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{})
+	logger.AddHook(hook)
+
+	logger.Debug("Filtered debug message")
+	logger.Info("Info message")
+	logger.Error("First error message")
+	logger.Error("Second error message")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "log_messages_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			counts[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+
+	expected := map[string]float64{"info": 1, "error": 2}
+	if len(counts) != len(expected) {
+		t.Errorf("Expected counts %v, got %v", expected, counts)
+	}
+	for level, count := range expected {
+		if counts[level] != count {
+			t.Errorf("Expected %v %s messages, got %v", count, level, counts[level])
+		}
+	}
+}
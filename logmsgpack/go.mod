@@ -0,0 +1,16 @@
+module github.com/pod32g/simple-logger/logmsgpack
+
+go 1.22.3
+
+require (
+	github.com/pod32g/simple-logger v0.0.0-00010101000000-000000000000
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/pod32g/simple-logger => ../
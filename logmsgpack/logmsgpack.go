@@ -0,0 +1,125 @@
+// Package logmsgpack encodes log entries as MessagePack for pipelines where bandwidth
+// matters more than readability. Importing the package registers the "msgpack" format
+// for LoggerConfig.Format:
+//
+//	import _ "github.com/pod32g/simple-logger/logmsgpack"
+//
+// It lives in its own module so that users of the core logger do not depend on a
+// MessagePack library.
+package logmsgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	log.RegisterFormatter("msgpack", func(config log.LoggerConfig) log.Formatter {
+		return &MsgpackFormatter{DisableCaller: !config.EnableCaller}
+	})
+}
+
+// MsgpackFormatter encodes each entry as a MessagePack map with "timestamp", "level",
+// "message", the caller's "file" and "line", and one key per structured field. A field
+// named like one of those keys is written as "fields."+key. Each record is prefixed with
+// its length as a 4-byte big-endian integer, see ReadRecord.
+type MsgpackFormatter struct {
+	DisableCaller bool // Omit the file and line of the caller
+}
+
+// Binary reports that the formatter's records must not be newline-terminated
+func (f *MsgpackFormatter) Binary() bool {
+	return true
+}
+
+// Format encodes a message logged without an entry
+func (f *MsgpackFormatter) Format(level log.LogLevel, message string) string {
+	return f.FormatEntry(log.Entry{Time: time.Now(), Level: level, Message: message})
+}
+
+// FormatEntry encodes the entry as a length-prefixed MessagePack map
+func (f *MsgpackFormatter) FormatEntry(e log.Entry) string {
+	caller := !f.DisableCaller && e.File != ""
+	size := 3 + len(e.Fields)
+	if caller {
+		size += 2
+	}
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4)) // Length prefix, filled in below
+	enc := msgpack.NewEncoder(&buf)
+	enc.EncodeMapLen(size)
+	enc.EncodeString("timestamp")
+	enc.EncodeTime(e.Time)
+	enc.EncodeString("level")
+	enc.EncodeString(e.Level.String())
+	enc.EncodeString("message")
+	enc.EncodeString(e.Message)
+	if caller {
+		enc.EncodeString("file")
+		enc.EncodeString(filepath.Base(e.File))
+		enc.EncodeString("line")
+		enc.EncodeInt(int64(e.Line))
+	}
+	for _, field := range e.Fields {
+		key := field.Key
+		if f.isStandardKey(key) {
+			key = "fields." + key
+		}
+		enc.EncodeString(key)
+		encodeValue(enc, &buf, field.Value)
+	}
+
+	record := buf.Bytes()
+	binary.BigEndian.PutUint32(record, uint32(len(record)-4))
+	return string(record)
+}
+
+// encodeValue encodes value, falling back to its %v form if MessagePack cannot encode it
+func encodeValue(enc *msgpack.Encoder, buf *bytes.Buffer, value interface{}) {
+	if err, ok := value.(error); ok {
+		enc.EncodeString(err.Error())
+		return
+	}
+	n := buf.Len()
+	if err := enc.Encode(value); err != nil {
+		buf.Truncate(n)
+		enc.EncodeString(fmt.Sprintf("%v", value))
+	}
+}
+
+// isStandardKey reports whether key is written by the formatter itself
+func (f *MsgpackFormatter) isStandardKey(key string) bool {
+	switch key {
+	case "timestamp", "level", "message":
+		return true
+	case "file", "line":
+		return !f.DisableCaller
+	}
+	return false
+}
+
+// ReadRecord reads one length-prefixed record written by MsgpackFormatter from r and
+// decodes it. It returns io.EOF when r has no more records.
+func ReadRecord(r io.Reader) (map[string]interface{}, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	body := make([]byte, binary.BigEndian.Uint32(prefix[:]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	var record map[string]interface{}
+	if err := msgpack.Unmarshal(body, &record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,58 @@
+package logmsgpack_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logmsgpack"
+)
+
+// TestMsgpackFormatter_RoundTrip verifies that standard and structured fields survive encoding and decoding
+func TestMsgpackFormatter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &logmsgpack.MsgpackFormatter{})
+
+	before := time.Now()
+	logger.WithFields(log.Fields{"user": "alice", "attempt": 2, "level": "custom"}).Warn("Login failed")
+	logger.Info("Second")
+
+	record, err := logmsgpack.ReadRecord(&buf)
+	if err != nil {
+		t.Fatalf("Expected a record, got error %v", err)
+	}
+	if record["level"] != "WARN" || record["message"] != "Login failed" {
+		t.Errorf("Expected level WARN and message 'Login failed', got %v", record)
+	}
+	if record["user"] != "alice" || record["attempt"] != int8(2) || record["fields.level"] != "custom" {
+		t.Errorf("Expected user, attempt and fields.level to round-trip, got %v", record)
+	}
+	if record["file"] != "logmsgpack_test.go" {
+		t.Errorf("Expected file 'logmsgpack_test.go', got %v", record["file"])
+	}
+	if timestamp, ok := record["timestamp"].(time.Time); !ok || timestamp.Before(before.Truncate(time.Second)) {
+		t.Errorf("Expected the log-call timestamp, got %v", record["timestamp"])
+	}
+
+	record, err = logmsgpack.ReadRecord(&buf)
+	if err != nil || record["message"] != "Second" {
+		t.Fatalf("Expected the second record directly after the first, got %v (error %v)", record, err)
+	}
+	if _, err := logmsgpack.ReadRecord(&buf); err != io.EOF {
+		t.Errorf("Expected io.EOF after the last record, got %v", err)
+	}
+}
+
+// TestMsgpackFormatter_Config verifies that importing the package registers the "msgpack" format
+func TestMsgpackFormatter_Config(t *testing.T) {
+	config := log.DefaultConfig()
+	config.Format = "msgpack"
+
+	logger := log.ApplyConfig(config)
+
+	if format := logger.Config().Format; format != "msgpack" {
+		t.Errorf("Expected format 'msgpack', got %q", format)
+	}
+}
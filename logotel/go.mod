@@ -0,0 +1,16 @@
+module github.com/pod32g/simple-logger/logotel
+
+go 1.22.3
+
+require (
+	github.com/pod32g/simple-logger v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	go.opentelemetry.io/otel v1.28.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/pod32g/simple-logger => ../
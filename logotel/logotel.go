@@ -0,0 +1,25 @@
+// Package logotel adds OpenTelemetry trace correlation to log entries.
+// It lives in its own module so that users of the core logger do not
+// depend on OpenTelemetry.
+package logotel
+
+import (
+	"context"
+
+	log "github.com/pod32g/simple-logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFields is a log.ContextExtractor that returns the "trace_id" and "span_id"
+// of the span in ctx, or nil if ctx carries no valid span. Register it with
+// Logger.AddContextExtractor so that Logger.WithContext adds both fields.
+func TraceFields(ctx context.Context) log.Fields {
+	span := trace.SpanContextFromContext(ctx)
+	if !span.IsValid() {
+		return nil
+	}
+	return log.Fields{
+		"trace_id": span.TraceID().String(),
+		"span_id":  span.SpanID().String(),
+	}
+}
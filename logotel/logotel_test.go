@@ -0,0 +1,63 @@
+package logotel_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logotel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newLogger returns a JSON logger with the trace extractor registered
+func newLogger(buf *bytes.Buffer) *log.Logger {
+	logger := log.NewLogger(buf, log.INFO, &log.JSONFormatter{})
+	logger.AddContextExtractor(logotel.TraceFields)
+	return logger
+}
+
+// TestTraceFields verifies that trace and span IDs from the context appear in JSON output
+func TestTraceFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), span)
+	logger.WithContext(ctx).Info("Traced")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["trace_id"] != "0102030405060708090a0b0c0d0e0f10" {
+		t.Errorf("Expected trace_id from the span context, got %v", entry["trace_id"])
+	}
+	if entry["span_id"] != "0a0b0c0d0e0f1011" {
+		t.Errorf("Expected span_id from the span context, got %v", entry["span_id"])
+	}
+}
+
+// TestTraceFields_NoSpan verifies that no IDs are added when the context has no span
+func TestTraceFields_NoSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(&buf)
+
+	logger.WithContext(context.Background()).Info("Untraced")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if _, ok := entry["trace_id"]; ok {
+		t.Errorf("Expected no trace_id without a span, got %v", entry)
+	}
+	if _, ok := entry["span_id"]; ok {
+		t.Errorf("Expected no span_id without a span, got %v", entry)
+	}
+}
@@ -0,0 +1,142 @@
+// Package logproto sends log entries to a gRPC logging service as protobuf messages.
+// The messages and service are defined in logentry.proto; entries are sent in batches
+// with the LogService.Write method from a background goroutine and retried when the
+// service is temporarily unavailable:
+//
+//	sink := logproto.NewSink(conn, logproto.Options{})
+//	defer sink.Close()
+//	logger.SetOutput(sink)
+//
+// It lives in its own module so that users of the core logger do not depend on gRPC
+// or protobuf.
+package logproto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative logentry.proto
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WriteMethod is the full name of the gRPC method the sink calls
+const WriteMethod = "/simplelogger.v1.LogService/Write"
+
+// Options configures how a Sink batches and retries entries
+type Options struct {
+	BatchSize    int             // Send once this many entries are queued, log.DefaultBatchSize if zero
+	BatchTimeout time.Duration   // Send queued entries after this delay even if the batch is not full, log.DefaultBatchTimeout if zero
+	MaxRetries   int             // Retries of a batch that failed with a transient error, log.DefaultMaxRetries if zero, none if negative
+	RetryBackoff time.Duration   // Delay before the first retry, doubled for each further one, log.DefaultRetryBackoff if zero
+	Timeout      time.Duration   // Deadline of each call, log.DefaultSendTimeout if zero
+	ErrorHandler func(err error) // Called with the error of a batch that failed in the background; if nil, the error is returned by the next Flush
+}
+
+// NewLogEntry converts e to a LogEntry. Field values are converted to strings, with
+// times in RFC 3339 format and other values in their %v form.
+func NewLogEntry(e log.Entry) *LogEntry {
+	entry := &LogEntry{
+		Time:     timestamppb.New(e.Time),
+		Level:    e.Level.String(),
+		Message:  e.Message,
+		File:     e.File,
+		Line:     int32(e.Line),
+		Function: e.Function,
+	}
+	if len(e.Fields) > 0 {
+		entry.Fields = make(map[string]string, len(e.Fields))
+		for _, field := range e.Fields {
+			entry.Fields[field.Key] = fieldString(field.Value)
+		}
+	}
+	return entry
+}
+
+// fieldString converts a field value to its string form
+func fieldString(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Sink is an output that sends entries to a LogService in batches. Entries are batched
+// and sent from a background goroutine by a log.Batcher, so a slow or unavailable
+// service never blocks the logging call; only transient errors are retried. Close
+// stops the goroutine.
+type Sink struct {
+	conn    grpc.ClientConnInterface
+	timeout time.Duration
+	batcher *log.Batcher[*LogEntry]
+}
+
+// NewSink creates a Sink that sends entries over conn
+func NewSink(conn grpc.ClientConnInterface, options Options) *Sink {
+	s := &Sink{conn: conn, timeout: options.Timeout}
+	if s.timeout <= 0 {
+		s.timeout = log.DefaultSendTimeout
+	}
+	s.batcher = log.NewBatcher(s.send, log.BatchOptions{
+		BatchSize:    options.BatchSize,
+		BatchTimeout: options.BatchTimeout,
+		MaxRetries:   options.MaxRetries,
+		RetryBackoff: options.RetryBackoff,
+		Retryable:    isTransient,
+		ErrorHandler: options.ErrorHandler,
+	})
+	return s
+}
+
+// WriteEntry queues e, handing the batch to the sender if it is full
+func (s *Sink) WriteEntry(e log.Entry, formatted string) error {
+	return s.batcher.Add(NewLogEntry(e))
+}
+
+// Write queues p as the message of an entry without a level, for text written to the
+// sink directly rather than through a logger
+func (s *Sink) Write(p []byte) (int, error) {
+	if err := s.batcher.Add(&LogEntry{Time: timestamppb.Now(), Message: strings.TrimRight(string(p), "\n")}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends any queued entries and waits until every batch handed to the sender so
+// far has been sent. It returns the error of the last batch, or, without an
+// ErrorHandler, of an earlier batch that failed in the background.
+func (s *Sink) Flush() error {
+	return s.batcher.Flush()
+}
+
+// Close sends any queued entries and stops the sender. It does not close the
+// connection, which belongs to the caller.
+func (s *Sink) Close() error {
+	return s.batcher.Close()
+}
+
+// send calls Write with a batch of entries
+func (s *Sink) send(entries []*LogEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.conn.Invoke(ctx, WriteMethod, &WriteRequest{Entries: entries}, &WriteResponse{})
+}
+
+// isTransient reports whether a call that failed with err may succeed if retried
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package logproto_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logproto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeLogService records the entries it receives, failing the first failures calls
+// with code, or Unavailable if unset. With block set, every call waits for it to be
+// closed first.
+type fakeLogService struct {
+	mu       sync.Mutex
+	failures int
+	code     codes.Code
+	calls    int
+	entries  []*logproto.LogEntry
+	block    chan struct{}
+}
+
+func (s *fakeLogService) write(req *logproto.WriteRequest) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		if s.code != codes.OK {
+			return status.Error(s.code, "rejected")
+		}
+		return status.Error(codes.Unavailable, "try again")
+	}
+	s.entries = append(s.entries, req.Entries...)
+	return nil
+}
+
+func (s *fakeLogService) received() (int, []*logproto.LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls, append([]*logproto.LogEntry(nil), s.entries...)
+}
+
+// startFakeLogService serves service in memory and returns a connection to it
+func startFakeLogService(t *testing.T, service *fakeLogService) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "simplelogger.v1.LogService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Write",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(logproto.WriteRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return &logproto.WriteResponse{}, service.write(req)
+			},
+		}},
+	}, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to connect to the fake service: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestSink verifies that entries arrive with their level, message, and fields
+func TestSink(t *testing.T) {
+	service := &fakeLogService{}
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{BatchSize: 10})
+	logger := log.NewLogger(sink, log.DEBUG, &log.DefaultFormatter{})
+
+	logger.WithFields(log.Fields{"user": "alice", "attempts": 3}).Warn("Login failed")
+	logger.Debug("Second")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected the batch to be sent, got %v", err)
+	}
+
+	calls, entries := service.received()
+	if calls != 1 || len(entries) != 2 {
+		t.Fatalf("Expected 2 entries in 1 call, got %d entries in %d calls", len(entries), calls)
+	}
+	entry := entries[0]
+	if entry.Level != "WARN" || entry.Message != "Login failed" {
+		t.Errorf("Expected a WARN entry with the message, got %+v", entry)
+	}
+	if entry.Fields["user"] != "alice" || entry.Fields["attempts"] != "3" {
+		t.Errorf("Expected the fields as strings, got %v", entry.Fields)
+	}
+	if entry.File == "" || entry.Line == 0 {
+		t.Errorf("Expected the caller, got %+v", entry)
+	}
+	if time.Since(entry.Time.AsTime()) > time.Minute {
+		t.Errorf("Expected the entry's timestamp, got %v", entry.Time)
+	}
+	if entries[1].Level != "DEBUG" || entries[1].Message != "Second" || entries[1].Fields != nil {
+		t.Errorf("Expected a DEBUG entry without fields, got %+v", entries[1])
+	}
+}
+
+// TestSink_BatchSize verifies that a full batch is sent without waiting for a flush
+func TestSink_BatchSize(t *testing.T) {
+	service := &fakeLogService{}
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{BatchSize: 2, BatchTimeout: time.Hour})
+	logger := log.NewLogger(sink, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("One")
+	logger.Info("Two")
+
+	// The batch is sent in the background; wait for it without flushing
+	deadline := time.Now().Add(time.Second)
+	for calls, _ := service.received(); calls == 0 && time.Now().Before(deadline); calls, _ = service.received() {
+		time.Sleep(time.Millisecond)
+	}
+	if calls, entries := service.received(); calls != 1 || len(entries) != 2 {
+		t.Errorf("Expected the full batch to be sent, got %d entries in %d calls", len(entries), calls)
+	}
+}
+
+// TestSink_Retry verifies that a batch is retried after transient errors
+func TestSink_Retry(t *testing.T) {
+	service := &fakeLogService{failures: 2}
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{RetryBackoff: time.Millisecond})
+	logger := log.NewLogger(sink, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Retried")
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Expected the batch to be sent after retrying, got %v", err)
+	}
+
+	calls, entries := service.received()
+	if calls != 3 || len(entries) != 1 || entries[0].Message != "Retried" {
+		t.Errorf("Expected 1 entry after 3 calls, got %d entries in %d calls", len(entries), calls)
+	}
+}
+
+// TestSink_RetriesExhausted verifies that the error is returned once the retries run out
+func TestSink_RetriesExhausted(t *testing.T) {
+	service := &fakeLogService{failures: 10}
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{MaxRetries: 1, RetryBackoff: time.Millisecond})
+
+	sink.Write([]byte("Lost\n"))
+	err := sink.Flush()
+
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the Unavailable error, got %v", err)
+	}
+	if calls, _ := service.received(); calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+// TestSink_ErrorHandler verifies that a batch that fails in the background is reported to the ErrorHandler option
+// and that later entries are still sent
+func TestSink_ErrorHandler(t *testing.T) {
+	service := &fakeLogService{failures: 1}
+	reported := make(chan error, 10)
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{BatchSize: 1, MaxRetries: -1, ErrorHandler: func(err error) {
+		reported <- err
+	}})
+	logger := log.NewLogger(sink, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Lost")
+	if err := <-reported; status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the Unavailable error to be reported, got %v", err)
+	}
+	logger.Info("Next")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected the next batch to be sent, got %v", err)
+	}
+
+	if _, entries := service.received(); len(entries) != 1 || entries[0].Message != "Next" {
+		t.Errorf("Expected the entry after the failure to arrive, got %v", entries)
+	}
+}
+
+// TestSink_PermanentError verifies that errors other than transient ones are not retried
+func TestSink_PermanentError(t *testing.T) {
+	service := &fakeLogService{failures: 10, code: codes.InvalidArgument}
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{RetryBackoff: time.Millisecond})
+	defer sink.Close()
+
+	sink.Write([]byte("Rejected\n"))
+	if err := sink.Flush(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected the InvalidArgument error, got %v", err)
+	}
+	if calls, _ := service.received(); calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+// TestSink_Unresponsive verifies that a service that does not answer does not block logging
+func TestSink_Unresponsive(t *testing.T) {
+	service := &fakeLogService{block: make(chan struct{})}
+	sink := logproto.NewSink(startFakeLogService(t, service), logproto.Options{BatchSize: 1, BatchTimeout: time.Hour})
+	logger := log.NewLogger(sink, log.INFO, &log.DefaultFormatter{})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		logger.Info("Waiting")
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Expected logging not to wait for the service, took %v", elapsed)
+	}
+
+	close(service.block)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected the batches to be sent once the service answers, got %v", err)
+	}
+	if _, entries := service.received(); len(entries) != 5 {
+		t.Errorf("Expected all 5 entries to arrive, got %d", len(entries))
+	}
+}
+
+// TestNewLogEntry verifies that an entry survives encoding and decoding
+func TestNewLogEntry(t *testing.T) {
+	entry := logproto.NewLogEntry(log.Entry{
+		Time:     time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC),
+		Level:    log.ERROR,
+		Message:  "Failed",
+		Fields:   []log.Field{{Key: "error", Value: "timeout"}, {Key: "at", Value: time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)}},
+		File:     "main.go",
+		Line:     -1,
+		Function: "main.main",
+	})
+
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Expected the entry to encode, got %v", err)
+	}
+	decoded := new(logproto.LogEntry)
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Expected the entry to decode, got %v", err)
+	}
+	if !proto.Equal(decoded, entry) {
+		t.Errorf("Expected %v, got %v", entry, decoded)
+	}
+	if !decoded.Time.AsTime().Equal(time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC)) || decoded.Level != "ERROR" || decoded.Line != -1 {
+		t.Errorf("Expected the time, level and line to decode, got %v", decoded)
+	}
+	if decoded.Fields["at"] != "2024-05-01T11:00:00Z" {
+		t.Errorf("Expected times in fields in RFC 3339 format, got %v", decoded.Fields)
+	}
+}
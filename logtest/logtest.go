@@ -0,0 +1,96 @@
+// Package logtest provides helpers for asserting on log output in tests.
+package logtest
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// Entry is a log message recorded by a CaptureLogger
+type Entry struct {
+	Level   log.LogLevel
+	Message string
+	Fields  log.Fields
+}
+
+// CaptureLogger is a Logger that records every entry it logs instead of writing it
+type CaptureLogger struct {
+	*log.Logger
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewCaptureLogger creates a CaptureLogger that records messages at or above the given level
+func NewCaptureLogger(level log.LogLevel) *CaptureLogger {
+	c := &CaptureLogger{}
+	c.Logger = log.NewLogger(io.Discard, level, &captureFormatter{capture: c})
+	return c
+}
+
+// Entries returns a copy of the entries recorded so far
+func (c *CaptureLogger) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]Entry, len(c.entries))
+	copy(entries, c.entries)
+	return entries
+}
+
+// Reset discards all recorded entries
+func (c *CaptureLogger) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+// Logged reports whether an entry at the given level containing substring was recorded
+func (c *CaptureLogger) Logged(level log.LogLevel, substring string) bool {
+	for _, entry := range c.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertLogged fails the test if no entry at the given level containing substring was recorded
+func (c *CaptureLogger) AssertLogged(t testing.TB, level log.LogLevel, substring string) {
+	t.Helper()
+	if !c.Logged(level, substring) {
+		t.Errorf("Expected a %s entry containing %q, got %v", level, substring, c.Entries())
+	}
+}
+
+// AssertNotLogged fails the test if an entry at the given level containing substring was recorded
+func (c *CaptureLogger) AssertNotLogged(t testing.TB, level log.LogLevel, substring string) {
+	t.Helper()
+	if c.Logged(level, substring) {
+		t.Errorf("Expected no %s entry containing %q, got %v", level, substring, c.Entries())
+	}
+}
+
+// captureFormatter records each message on its CaptureLogger and produces no output
+type captureFormatter struct {
+	capture *CaptureLogger
+}
+
+func (f *captureFormatter) Format(level log.LogLevel, message string) string {
+	return f.FormatEntry(log.Entry{Level: level, Message: message})
+}
+
+func (f *captureFormatter) FormatEntry(e log.Entry) string {
+	fields := make(log.Fields, len(e.Fields))
+	for _, field := range e.Fields {
+		fields[field.Key] = field.Value
+	}
+
+	f.capture.mu.Lock()
+	defer f.capture.mu.Unlock()
+	f.capture.entries = append(f.capture.entries, Entry{Level: e.Level, Message: e.Message, Fields: fields})
+	return ""
+}
@@ -0,0 +1,69 @@
+package logtest_test
+
+import (
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestCaptureLogger_Entries verifies that logged messages are recorded with their level
+func TestCaptureLogger_Entries(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+
+	logger.Debug("Debug message")
+	logger.Error("Error message")
+
+	entries := logger.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Level != log.DEBUG || entries[0].Message != "Debug message" {
+		t.Errorf("Expected DEBUG 'Debug message', got %v", entries[0])
+	}
+	if entries[1].Level != log.ERROR || entries[1].Message != "Error message" {
+		t.Errorf("Expected ERROR 'Error message', got %v", entries[1])
+	}
+}
+
+// TestCaptureLogger_LevelFiltering verifies that messages below the level are not recorded
+func TestCaptureLogger_LevelFiltering(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.WARN)
+
+	logger.Info("Info message")
+	logger.Warn("Warn message")
+
+	logger.AssertLogged(t, log.WARN, "Warn")
+	logger.AssertNotLogged(t, log.INFO, "Info")
+	if len(logger.Entries()) != 1 {
+		t.Errorf("Expected 1 entry, got %v", logger.Entries())
+	}
+}
+
+// TestCaptureLogger_Reset verifies that Reset discards recorded entries
+func TestCaptureLogger_Reset(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+
+	logger.Info("Info message")
+	logger.Reset()
+
+	if len(logger.Entries()) != 0 {
+		t.Errorf("Expected no entries after Reset, got %v", logger.Entries())
+	}
+}
+
+// TestCaptureLogger_Fields verifies that structured fields are recorded with the entry
+func TestCaptureLogger_Fields(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+
+	logger.WithFields(log.Fields{"user": "alice", "attempt": 2}).Error("Login failed")
+
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Fields["user"] != "alice" || entries[0].Fields["attempt"] != 2 {
+		t.Errorf("Expected user and attempt fields, got %v", entries[0].Fields)
+	}
+	logger.AssertLogged(t, log.ERROR, "Login failed")
+}
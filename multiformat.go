@@ -0,0 +1,86 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// FormatTarget pairs a formatter with the writer that receives its output
+type FormatTarget struct {
+	Formatter Formatter
+	Writer    io.Writer
+}
+
+// MultiFormatWriter is an output that writes every entry to several writers, each with
+// its own formatter, for example console text to stderr and JSON to a file. All targets
+// format the same captured entry, so they agree on its time and caller. The text
+// formatted by the logger's own formatter is not used.
+type MultiFormatWriter struct {
+	mu      sync.Mutex
+	targets []FormatTarget
+}
+
+// NewMultiFormatWriter returns a MultiFormatWriter writing to the given targets
+func NewMultiFormatWriter(targets ...FormatTarget) *MultiFormatWriter {
+	return &MultiFormatWriter{targets: append([]FormatTarget(nil), targets...)}
+}
+
+// WriteEntry formats the entry once per target and writes it to the target's writer.
+// A failing target does not stop the others; their errors are joined.
+func (w *MultiFormatWriter) WriteEntry(e Entry, formatted string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var errs []error
+	for _, target := range w.targets {
+		text := format(target.Formatter, e)
+		if text != "" && !strings.HasSuffix(text, "\n") && !isBinary(target.Formatter) {
+			text += "\n"
+		}
+		if _, err := io.WriteString(target.Writer, text); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Write writes p unchanged to every target's writer
+func (w *MultiFormatWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var errs []error
+	for _, target := range w.targets {
+		if _, err := target.Writer.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// Flush flushes every target's writer that supports it
+func (w *MultiFormatWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var errs []error
+	for _, target := range w.targets {
+		switch writer := target.Writer.(type) {
+		case Flusher:
+			errs = append(errs, writer.Flush())
+		case syncer:
+			errs = append(errs, syncOutput(writer))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reportsCaller reports whether any target's formatter renders caller information,
+// so that the logger captures the caller even if its own formatter does not
+func (w *MultiFormatWriter) reportsCaller() bool {
+	for _, target := range w.targets {
+		if wantsCaller(target.Formatter) {
+			return true
+		}
+	}
+	return false
+}
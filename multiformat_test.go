@@ -0,0 +1,41 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestMultiFormatWriter verifies that every target formats the same entry with its own formatter
+func TestMultiFormatWriter(t *testing.T) {
+	var text, jsonOut bytes.Buffer
+	output := log.NewMultiFormatWriter(
+		log.FormatTarget{Formatter: &log.KeyValueFormatter{}, Writer: &text},
+		log.FormatTarget{Formatter: &log.JSONFormatter{}, Writer: &jsonOut},
+	)
+	logger := log.NewLogger(output, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.SetClock(fixedClock{time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)})
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.WithField("user", "alice").Info("Both outputs")
+
+	expected := fmt.Sprintf("time=2024-03-01T12:30:00Z level=INFO caller=multiformat_test.go:%d msg=\"Both outputs\" user=alice\n", line+1)
+	if text.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, text.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(jsonOut.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["timestamp"] != "2024-03-01T12:30:00Z" || entry["file"] != "multiformat_test.go" || entry["line"] != float64(line+1) {
+		t.Errorf("Expected the same timestamp and caller as the text output, got %v", entry)
+	}
+	if entry["user"] != "alice" {
+		t.Errorf("Expected the user field, got %v", entry)
+	}
+}
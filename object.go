@@ -0,0 +1,70 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// objectField describes a struct field extracted by WithObject
+type objectField struct {
+	index int
+	key   string
+}
+
+// objectFields caches the fields WithObject extracts, keyed by struct type
+var objectFields sync.Map
+
+// WithObject returns an Entry carrying the fields of v, see Entry.WithObject
+func (l *Logger) WithObject(v interface{}) *Entry {
+	return (&Entry{logger: l}).WithObject(v)
+}
+
+// WithObject returns a copy of the entry with each exported field of the struct v added
+// as a structured field. The key is taken from the field's `log:"name"` tag, or is the
+// field name if it has none; fields tagged `log:"-"`, such as secrets, are left out.
+// v may be a pointer to a struct; a nil pointer adds nothing. Any other value is
+// reported in the _field_error field.
+func (e *Entry) WithObject(v interface{}) *Entry {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return e.with(nil)
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return e.withProblems(nil, []string{fmt.Sprintf("object: %T is not a struct", v)})
+	}
+
+	fields := structFields(value.Type())
+	added := make([]Field, len(fields))
+	for i, field := range fields {
+		added[i] = Field{Key: field.key, Value: value.Field(field.index).Interface()}
+	}
+	return e.with(added)
+}
+
+// structFields returns the fields WithObject extracts from values of type t
+func structFields(t reflect.Type) []objectField {
+	if cached, ok := objectFields.Load(t); ok {
+		return cached.([]objectField)
+	}
+	var fields []objectField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, ok := field.Tag.Lookup("log")
+		if key == "-" {
+			continue
+		}
+		if !ok || key == "" {
+			key = field.Name
+		}
+		fields = append(fields, objectField{index: i, key: key})
+	}
+	objectFields.Store(t, fields)
+	return fields
+}
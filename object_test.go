@@ -0,0 +1,69 @@
+package log_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// account has tagged, untagged, omitted and unexported fields
+type account struct {
+	ID       int    `log:"account_id"`
+	Email    string `log:"email"`
+	Plan     string
+	Password string `log:"-"`
+	internal string
+}
+
+// TestEntry_WithObject verifies that tagged and untagged fields are extracted and omitted ones are not
+func TestEntry_WithObject(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	user := &account{ID: 7, Email: "alice@example.com", Plan: "pro", Password: "hunter2", internal: "x"}
+
+	logger.WithObject(user).WithField("action", "login").Info("Signed in")
+	logger.WithObject(*user).Info("By value")
+
+	expected := log.Fields{"account_id": 7, "email": "alice@example.com", "Plan": "pro", "action": "login"}
+	entries := logger.Entries()
+	if !reflect.DeepEqual(entries[0].Fields, expected) {
+		t.Errorf("Expected %v, got %v", expected, entries[0].Fields)
+	}
+	delete(expected, "action")
+	if !reflect.DeepEqual(entries[1].Fields, expected) {
+		t.Errorf("Expected %v from a struct value, got %v", expected, entries[1].Fields)
+	}
+}
+
+// TestEntry_WithObjectSecret verifies that a field tagged log:"-" never reaches the output
+func TestEntry_WithObjectSecret(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+
+	logger.WithObject(account{Password: "hunter2"}).Info("Signed in")
+
+	for _, entry := range logger.Entries() {
+		for key, value := range entry.Fields {
+			if key == "Password" || value == "hunter2" {
+				t.Errorf("Expected the password to be omitted, got %v", entry.Fields)
+			}
+		}
+	}
+}
+
+// TestEntry_WithObjectNotStruct verifies that a value that is not a struct is reported rather than logged
+func TestEntry_WithObjectNotStruct(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+
+	logger.WithObject("plain").Info("Not a struct")
+	logger.WithObject((*account)(nil)).Info("Nil pointer")
+
+	entries := logger.Entries()
+	if fieldError, ok := entries[0].Fields["_field_error"].(string); !ok || !strings.Contains(fieldError, "string is not a struct") {
+		t.Errorf("Expected a _field_error for a string, got %v", entries[0].Fields)
+	}
+	if len(entries[1].Fields) != 0 {
+		t.Errorf("Expected no fields for a nil pointer, got %v", entries[1].Fields)
+	}
+}
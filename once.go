@@ -0,0 +1,38 @@
+package log
+
+// logOnce logs the message the first time key is seen at a level the logger writes.
+// Keys are shared with the loggers derived from this one so a warning is not repeated by a named copy.
+func (l *Logger) logOnce(level LogLevel, key string, v ...interface{}) {
+	if !l.IsLevelEnabled(level) {
+		return
+	}
+	if _, seen := l.onceKeys.LoadOrStore(key, struct{}{}); seen {
+		return
+	}
+	l.log(level, nil, v...)
+}
+
+// DebugOnce logs a debug message only the first time key is used
+func (l *Logger) DebugOnce(key string, v ...interface{}) {
+	l.logOnce(DEBUG, key, v...)
+}
+
+// InfoOnce logs an info message only the first time key is used
+func (l *Logger) InfoOnce(key string, v ...interface{}) {
+	l.logOnce(INFO, key, v...)
+}
+
+// NoticeOnce logs a notice message only the first time key is used
+func (l *Logger) NoticeOnce(key string, v ...interface{}) {
+	l.logOnce(NOTICE, key, v...)
+}
+
+// WarnOnce logs a warning message only the first time key is used, for example a deprecation notice
+func (l *Logger) WarnOnce(key string, v ...interface{}) {
+	l.logOnce(WARN, key, v...)
+}
+
+// ErrorOnce logs an error message only the first time key is used
+func (l *Logger) ErrorOnce(key string, v ...interface{}) {
+	l.logOnce(ERROR, key, v...)
+}
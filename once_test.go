@@ -0,0 +1,84 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestLogger_WarnOnce verifies that a keyed warning is written exactly once
+func TestLogger_WarnOnce(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	for i := 0; i < 3; i++ {
+		logger.WarnOnce("TestLogger_WarnOnce", "Deprecated option used")
+	}
+
+	if count := strings.Count(buf.String(), "Deprecated option used"); count != 1 {
+		t.Errorf("Expected exactly one line, got %d: %v", count, buf.String())
+	}
+}
+
+// TestLogger_WarnOnceConcurrent verifies that concurrent callers still produce a single line
+func TestLogger_WarnOnceConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.WarnOnce("TestLogger_WarnOnceConcurrent", "Concurrent warning")
+		}()
+	}
+	wg.Wait()
+
+	if count := strings.Count(buf.String(), "Concurrent warning"); count != 1 {
+		t.Errorf("Expected exactly one line, got %d: %v", count, buf.String())
+	}
+}
+
+// TestLogger_WarnOnceDerived verifies that derived loggers share keys while a separate logger has its own
+func TestLogger_WarnOnceDerived(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+	other := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	logger.WarnOnce("TestLogger_WarnOnceDerived", "Derived warning")
+	logger.Named("db").WarnOnce("TestLogger_WarnOnceDerived", "Derived warning")
+	other.WarnOnce("TestLogger_WarnOnceDerived", "Derived warning")
+
+	if count := strings.Count(buf.String(), "Derived warning"); count != 2 {
+		t.Errorf("Expected one line per logger, got %d: %v", count, buf.String())
+	}
+}
+
+// TestLogger_DebugOnceFiltered verifies that a filtered message does not consume its key
+func TestLogger_DebugOnceFiltered(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{})
+
+	logger.DebugOnce("TestLogger_DebugOnceFiltered", "Debug once")
+	logger.SetLevel(log.DEBUG)
+	logger.DebugOnce("TestLogger_DebugOnceFiltered", "Debug once")
+
+	if count := strings.Count(buf.String(), "Debug once"); count != 1 {
+		t.Errorf("Expected exactly one line, got %d: %v", count, buf.String())
+	}
+}
+
+// TestLogger_DebugOncePackageLevel verifies that a package level below the logger's level enables the *Once methods
+func TestLogger_DebugOncePackageLevel(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.WARN)
+	logger.SetPackageLevel("github.com/pod32g/simple-logger", log.DEBUG)
+
+	logger.DebugOnce("TestLogger_DebugOncePackageLevel", "Debug once from the tests")
+
+	logger.AssertLogged(t, log.DEBUG, "Debug once from the tests")
+}
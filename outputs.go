@@ -0,0 +1,39 @@
+package log
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// EntryWriter is implemented by outputs that record entries in a structured form, such as
+// the systemd journal. When a logger's output implements EntryWriter, WriteEntry is called
+// with the entry and its formatted text instead of Write.
+type EntryWriter interface {
+	WriteEntry(e Entry, formatted string) error
+}
+
+// OutputFactory opens an output from the logger configuration
+type OutputFactory func(config LoggerConfig) (io.Writer, error)
+
+var (
+	outputsMu sync.RWMutex
+	outputs   = map[string]OutputFactory{}
+)
+
+// RegisterOutput makes an output available to ApplyConfig under the given case-insensitive
+// name, taking precedence over a file of the same name. Registering an existing name
+// replaces its factory. Outputs that implement io.Closer are closed on Shutdown.
+func RegisterOutput(name string, factory OutputFactory) {
+	outputsMu.Lock()
+	defer outputsMu.Unlock()
+	outputs[strings.ToLower(name)] = factory
+}
+
+// lookupOutput returns the factory registered for name, if any
+func lookupOutput(name string) (OutputFactory, bool) {
+	outputsMu.RLock()
+	defer outputsMu.RUnlock()
+	factory, ok := outputs[strings.ToLower(name)]
+	return factory, ok
+}
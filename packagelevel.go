@@ -0,0 +1,66 @@
+package log
+
+import "strings"
+
+// packageLevel is a level set with SetPackageLevel for callers under an import path prefix
+type packageLevel struct {
+	prefix string
+	level  LogLevel
+}
+
+// SetPackageLevel sets the level for messages logged from packages whose import path
+// starts with prefix, overriding the logger's level in either direction. The prefix
+// may be a full import path or one relative to the main module, such as
+// "internal/payments", and matches whole path elements. When several prefixes match,
+// the longest wins. Setting any package level makes the logger look up the caller of
+// every call, including calls that end up filtered out.
+func (l *Logger) SetPackageLevel(prefix string, level LogLevel) {
+	prefix = strings.Trim(prefix, "/")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var levels []packageLevel
+	if current := l.packageLevels.Load(); current != nil {
+		levels = append(levels, *current...)
+	}
+	replaced := false
+	for i := range levels {
+		if levels[i].prefix == prefix {
+			levels[i].level = level
+			replaced = true
+		}
+	}
+	if !replaced {
+		levels = append(levels, packageLevel{prefix: prefix, level: level})
+	}
+	l.packageLevels.Store(&levels)
+}
+
+// levelFor returns the level that applies to messages logged from function: that of the
+// longest matching prefix, comparing relative prefixes as if the main module's path were
+// prepended, or fallback if none matches
+func levelFor(levels []packageLevel, function string, fallback LogLevel) LogLevel {
+	// External test packages share the directory of the package they test
+	pkg := strings.TrimSuffix(functionPackage(function), "_test")
+	relative := ""
+	if mainModule != "" && strings.HasPrefix(pkg, mainModule+"/") {
+		relative = strings.TrimPrefix(pkg, mainModule+"/")
+	}
+	result, longest := fallback, -1
+	for _, level := range levels {
+		length := -1
+		if hasPathPrefix(pkg, level.prefix) {
+			length = len(level.prefix)
+		} else if relative != "" && hasPathPrefix(relative, level.prefix) {
+			length = len(mainModule) + 1 + len(level.prefix)
+		}
+		if length > longest {
+			result, longest = level.level, length
+		}
+	}
+	return result
+}
+
+// hasPathPrefix reports whether path is prefix or lies below it
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
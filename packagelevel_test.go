@@ -0,0 +1,39 @@
+package log_test
+
+import (
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/internal/pkglog"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestLogger_SetPackageLevel verifies that the most specific package prefix decides the level
+func TestLogger_SetPackageLevel(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.WARN)
+	logger.SetPackageLevel("github.com/pod32g/simple-logger", log.DEBUG)
+	logger.SetPackageLevel("internal/pkglog", log.ERROR)
+
+	logger.Debug("Debug from the tests")
+	logger.Debugw("Sugared debug from the tests", "key", "value")
+	pkglog.Info(logger.Logger, "Info from pkglog")
+
+	logger.AssertLogged(t, log.DEBUG, "Debug from the tests")
+	logger.AssertLogged(t, log.DEBUG, "Sugared debug from the tests")
+	logger.AssertNotLogged(t, log.INFO, "Info from pkglog")
+}
+
+// TestLogger_SetPackageLevelRelative verifies that a prefix relative to the main module raises one package above the logger level
+func TestLogger_SetPackageLevelRelative(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.WARN)
+	logger.SetPackageLevel("internal/pkglog", log.INFO)
+
+	logger.Info("Info from the tests")
+	pkglog.Info(logger.Logger, "Info from pkglog")
+
+	logger.AssertNotLogged(t, log.INFO, "Info from the tests")
+	logger.AssertLogged(t, log.INFO, "Info from pkglog")
+	if entries := logger.Entries(); len(entries) != 1 {
+		t.Errorf("Expected only the pkglog entry, got %v", entries)
+	}
+}
@@ -0,0 +1,18 @@
+package log
+
+import "os"
+
+// NewDevelopmentLogger creates a logger for local development: DEBUG level and
+// colored console output with caller information, written to stdout.
+func NewDevelopmentLogger() *Logger {
+	return NewLogger(os.Stdout, DEBUG, &ConsoleFormatter{})
+}
+
+// NewProductionLogger creates a logger for production: INFO level and JSON output
+// with UTC timestamps and caller information, written to stdout. Messages at ERROR
+// and above carry a stack trace.
+func NewProductionLogger() *Logger {
+	logger := NewLogger(os.Stdout, INFO, &JSONFormatter{UTC: true})
+	logger.SetStacktraceLevel(ERROR)
+	return logger
+}
@@ -0,0 +1,68 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestNewDevelopmentLogger verifies that the development preset logs DEBUG messages as colored console lines
+func TestNewDevelopmentLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewDevelopmentLogger()
+	logger.SetOutput(&buf)
+
+	logger.Debug("Debug message")
+
+	if !strings.Contains(buf.String(), "\x1b[90mDEBUG\x1b[0m  presets_test.go:") {
+		t.Errorf("Expected a colored console line with caller, got %q", buf.String())
+	}
+}
+
+// TestNewProductionLogger verifies that the production preset logs UTC JSON at INFO and above
+func TestNewProductionLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewProductionLogger()
+	logger.SetOutput(&buf)
+
+	logger.Debug("Debug message")
+	if buf.String() != "" {
+		t.Fatalf("Expected no output for Debug message, got %v", buf.String())
+	}
+
+	logger.Info("Info message")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if timestamp, _ := entry["timestamp"].(string); !strings.HasSuffix(timestamp, "Z") {
+		t.Errorf("Expected a UTC timestamp, got %v", entry["timestamp"])
+	}
+	if entry["file"] != "presets_test.go" {
+		t.Errorf("Expected caller file presets_test.go, got %v", entry["file"])
+	}
+	if _, ok := entry["stacktrace"]; ok {
+		t.Errorf("Expected no stacktrace on an Info message, got %v", entry["stacktrace"])
+	}
+}
+
+// TestNewProductionLogger_Stacktrace verifies that the production preset attaches a stack trace to errors
+func TestNewProductionLogger_Stacktrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewProductionLogger()
+	logger.SetOutput(&buf)
+
+	logger.Error("Error message")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	stacktrace, _ := entry["stacktrace"].(string)
+	if !strings.HasPrefix(stacktrace, "github.com/pod32g/simple-logger_test.TestNewProductionLogger_Stacktrace\n\t") {
+		t.Errorf("Expected the stack trace to start at the test function, got %v", stacktrace)
+	}
+}
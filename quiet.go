@@ -0,0 +1,175 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultQuietWindow is the window used for LoggerConfig.QuietAfter
+const DefaultQuietWindow = 10 * time.Second
+
+// maxQuietMessages bounds how many distinct messages a quiet filter counts at once, so
+// that messages with unique text, such as ones embedding request IDs, cannot grow it
+// without limit. Once it is reached, new messages are written unfiltered until a window ends.
+const maxQuietMessages = 1024
+
+// quietFilter counts repeated messages for SetQuietAfter
+type quietFilter struct {
+	after  int
+	window time.Duration
+
+	mu      sync.Mutex
+	counts  map[quietKey]*quietCount
+	sweep   *time.Timer // Ends the windows that have expired, nil while no message is counted
+	stopped bool        // Set by Shutdown; messages are no longer counted
+}
+
+// quietKey identifies a repeated message
+type quietKey struct {
+	level   LogLevel
+	message string
+}
+
+// quietCount is the number of times a message was seen in its current window
+type quietCount struct {
+	seen   int
+	start  time.Time
+	clock  Clock   // Clock of the logger that started the window, which measures its length
+	logger *Logger // Logger that started the window, which logs the summary
+}
+
+// SetQuietAfter limits how often the same message is written: of the messages logged
+// with the same level and text within window of the first, only the first n are
+// written. FATAL messages are never limited. The rest are counted, and when the window ends a single summary reports
+// how many were suppressed, for example while a service logs "waiting for dependency"
+// during startup. A window starts again with the next occurrence. Zero n disables
+// the limit. At most 1024 distinct messages are counted at once; beyond that, new
+// messages are written unfiltered until a window ends.
+func (l *Logger) SetQuietAfter(n int, window time.Duration) {
+	quiet := newQuietFilter(n, window)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.quiet = quiet
+}
+
+// newQuietFilter returns the filter for SetQuietAfter, or nil if n disables it
+func newQuietFilter(n int, window time.Duration) *quietFilter {
+	if n <= 0 {
+		return nil
+	}
+	if window <= 0 {
+		window = DefaultQuietWindow
+	}
+	return &quietFilter{after: n, window: window, counts: make(map[quietKey]*quietCount)}
+}
+
+// allow reports whether the entry should be written, starting a window for its message
+// at the entry's time if it is the first occurrence. FATAL messages are always written,
+// so that Fatal exits and no summary is ever logged through the fatal exit path.
+func (q *quietFilter) allow(l *Logger, clock Clock, entry Entry) bool {
+	if entry.Level == FATAL {
+		return true
+	}
+	key := quietKey{level: entry.Level, message: entry.Message}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return true
+	}
+	count, ok := q.counts[key]
+	if !ok {
+		if len(q.counts) >= maxQuietMessages {
+			return true
+		}
+		count = &quietCount{start: entry.Time, clock: clock, logger: l}
+		q.counts[key] = count
+		if q.sweep == nil {
+			q.sweep = time.AfterFunc(q.window, q.endWindows)
+		}
+	}
+	count.seen++
+	return count.seen <= q.after
+}
+
+// endWindows forgets the messages whose window has ended, logging how many occurrences
+// of each were suppressed, and schedules itself for the next window to end
+func (q *quietFilter) endWindows() {
+	var summaries []endedWindow
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	var next time.Duration
+	for key, count := range q.counts {
+		remaining := q.window - count.clock.Now().Sub(count.start)
+		if remaining > 0 {
+			if next == 0 || remaining < next {
+				next = remaining
+			}
+			continue
+		}
+		delete(q.counts, key)
+		if count.seen > q.after {
+			summaries = append(summaries, endedWindow{key, count})
+		}
+	}
+	q.sweep = nil
+	if len(q.counts) > 0 {
+		q.sweep = time.AfterFunc(next, q.endWindows)
+	}
+	q.mu.Unlock()
+	q.summarizeAll(summaries)
+}
+
+// stop ends every window for Shutdown, logging the summaries of those that suppressed
+// messages, and stops the sweep timer. Messages logged afterwards are not counted.
+func (q *quietFilter) stop() {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	if q.sweep != nil {
+		q.sweep.Stop()
+		q.sweep = nil
+	}
+	var summaries []endedWindow
+	for key, count := range q.counts {
+		if count.seen > q.after {
+			summaries = append(summaries, endedWindow{key, count})
+		}
+	}
+	q.counts = make(map[quietKey]*quietCount)
+	q.mu.Unlock()
+	q.summarizeAll(summaries)
+}
+
+// endedWindow is a message whose window ended after some occurrences were suppressed
+type endedWindow struct {
+	key   quietKey
+	count *quietCount
+}
+
+// summarizeAll logs the summaries of windows in the order the windows started
+func (q *quietFilter) summarizeAll(summaries []endedWindow) {
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].count.start.Before(summaries[j].count.start)
+	})
+	for _, s := range summaries {
+		q.summarize(s.count.logger, s.key, s.count.seen-q.after)
+	}
+}
+
+// summarize logs how many occurrences of key were suppressed in the window that ended
+func (q *quietFilter) summarize(l *Logger, key quietKey, suppressed int) {
+	// The summary is logged from the timer's goroutine, so it has no meaningful caller
+	summary := l.clone()
+	summary.quiet = nil
+	summary.callerMinLevel = OFF
+	message := fmt.Sprintf("%s (suppressed %d more times in %v)", key.message, suppressed, q.window)
+	summary.log(key.level, []Field{{Key: "suppressed", Value: suppressed}}, message)
+}
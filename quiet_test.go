@@ -0,0 +1,165 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_SetQuietAfter verifies that a repeated message is written n times and the
+// rest are summarized when the window ends
+func TestLogger_SetQuietAfter(t *testing.T) {
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	logger := log.NewLogger(&lockedWriter{mu: &mu, w: &buf}, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.SetQuietAfter(3, 50*time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		logger.Info("Waiting for dependency")
+	}
+	logger.Warn("Waiting for dependency")
+	logger.Info("Other message")
+
+	mu.Lock()
+	output := buf.String()
+	mu.Unlock()
+	if count := strings.Count(output, "[INFO] Waiting for dependency\n"); count != 3 {
+		t.Errorf("Expected the repeated message 3 times, got %d in %q", count, output)
+	}
+	if !strings.Contains(output, "[WARN] Waiting for dependency") || !strings.Contains(output, "Other message") {
+		t.Errorf("Expected other levels and messages to be counted separately, got %q", output)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		output = buf.String()
+		mu.Unlock()
+		if strings.Contains(output, "suppressed") || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(output, "Waiting for dependency (suppressed 17 more times in 50ms) suppressed=17") {
+		t.Errorf("Expected a summary of the 17 suppressed messages, got %q", output)
+	}
+	if strings.Count(output, "suppressed") != 2 {
+		t.Errorf("Expected only the repeated message to be summarized, got %q", output)
+	}
+
+	logger.Info("Waiting for dependency")
+	mu.Lock()
+	output = buf.String()
+	mu.Unlock()
+	if count := strings.Count(output, "[INFO] Waiting for dependency\n"); count != 4 {
+		t.Errorf("Expected the message to be written again in a new window, got %q", output)
+	}
+}
+
+// TestLogger_SetQuietAfterClock verifies that quiet windows are measured with the logger's clock
+func TestLogger_SetQuietAfterClock(t *testing.T) {
+	var mu, clockMu sync.Mutex
+	var buf bytes.Buffer
+	current := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	logger := log.NewLogger(&lockedWriter{mu: &mu, w: &buf}, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.SetClock(clockFunc(func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return current
+	}))
+	logger.SetQuietAfter(1, 20*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		logger.Info("Waiting for dependency")
+	}
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	output := buf.String()
+	mu.Unlock()
+	if strings.Contains(output, "suppressed") {
+		t.Fatalf("Expected the window to stay open while the clock stands still, got %q", output)
+	}
+
+	clockMu.Lock()
+	current = current.Add(20 * time.Millisecond)
+	clockMu.Unlock()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		output = buf.String()
+		mu.Unlock()
+		if strings.Contains(output, "suppressed") || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(output, "(suppressed 2 more times in 20ms)") {
+		t.Errorf("Expected a summary once the clock passed the window, got %q", output)
+	}
+}
+
+// TestLogger_SetQuietAfterFatal verifies that FATAL messages are never suppressed or summarized
+func TestLogger_SetQuietAfterFatal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	var exits int
+	logger.SetExitFunc(func(int) { exits++ })
+	logger.SetQuietAfter(1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		logger.Fatal("Cannot continue")
+	}
+	logger.Shutdown(context.Background())
+
+	if count := strings.Count(buf.String(), "[FATAL] Cannot continue\n"); count != 3 || exits != 3 {
+		t.Errorf("Expected every FATAL message to be written and exit, got %d exits in %q", exits, buf.String())
+	}
+	if strings.Contains(buf.String(), "suppressed") {
+		t.Errorf("Expected no summary for FATAL messages, got %q", buf.String())
+	}
+}
+
+// TestLogger_SetQuietAfterDistinctMessages verifies that messages with unique text do not grow the filter without bound
+func TestLogger_SetQuietAfterDistinctMessages(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.SetQuietAfter(1, time.Hour)
+
+	for i := 0; i < log.MaxQuietMessages+100; i++ {
+		logger.Info(fmt.Sprintf("Request %d failed", i))
+	}
+	logger.Info("Untracked")
+	logger.Info("Untracked")
+
+	if tracked := logger.QuietMessages(); tracked != log.MaxQuietMessages {
+		t.Errorf("Expected %d messages to be counted, got %d", log.MaxQuietMessages, tracked)
+	}
+	if count := strings.Count(buf.String(), "Untracked"); count != 2 {
+		t.Errorf("Expected messages beyond the limit to be written unfiltered, got %d", count)
+	}
+}
+
+// TestLogger_SetQuietAfterWindowsEnd verifies that counted messages are forgotten once their window ends
+func TestLogger_SetQuietAfterWindowsEnd(t *testing.T) {
+	logger := log.NewLogger(io.Discard, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	logger.SetQuietAfter(1, 20*time.Millisecond)
+
+	for i := 0; i < 100; i++ {
+		logger.Info(fmt.Sprintf("Request %d failed", i))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for logger.QuietMessages() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if tracked := logger.QuietMessages(); tracked != 0 {
+		t.Errorf("Expected every window to end, got %d messages still counted", tracked)
+	}
+}
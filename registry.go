@@ -0,0 +1,64 @@
+package log
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	registryMu    sync.RWMutex
+	registry      = map[string]*Logger{}
+	defaultLogger = NewLogger(os.Stdout, INFO, &DefaultFormatter{})
+)
+
+// Default returns the logger that For copies for unregistered names.
+// Unless replaced with SetDefault it writes text at INFO level to stdout.
+func Default() *Logger {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces the logger that For copies for unregistered names.
+// Loggers already returned by For are not affected.
+func SetDefault(l *Logger) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	defaultLogger = l
+}
+
+// Register makes l available as For(name), replacing any logger registered under name
+func Register(name string, l *Logger) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = l
+}
+
+// Unregister removes the logger registered under name, so the next For(name) returns a new copy of the default logger
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, name)
+}
+
+// For returns the logger registered under name. For an unknown name it registers and
+// returns a copy of the default logger, so later calls return the same logger and its
+// level can be changed without affecting other names. The copy shares the default
+// logger's output.
+func For(name string) *Logger {
+	registryMu.RLock()
+	l, ok := registry[name]
+	registryMu.RUnlock()
+	if ok {
+		return l
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if l, ok := registry[name]; ok {
+		return l
+	}
+	l = defaultLogger.clone()
+	registry[name] = l
+	return l
+}
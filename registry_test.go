@@ -0,0 +1,86 @@
+package log_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestRegistry_IndependentLevels verifies that registered loggers are returned by name and keep their own levels
+func TestRegistry_IndependentLevels(t *testing.T) {
+	var payments, auth bytes.Buffer
+	t.Cleanup(func() {
+		log.Unregister("payments")
+		log.Unregister("auth")
+	})
+	log.Register("payments", log.NewLogger(&payments, log.INFO, &log.DefaultFormatter{}))
+	log.Register("auth", log.NewLogger(&auth, log.INFO, &log.DefaultFormatter{}))
+
+	log.For("auth").SetLevel(log.ERROR)
+	log.For("payments").Info("Charge accepted")
+	log.For("auth").Info("Login attempt")
+
+	if !containsLogMessage(payments.String(), "INFO", "Charge accepted") {
+		t.Errorf("Expected the payments logger to write at INFO, got %q", payments.String())
+	}
+	if auth.String() != "" {
+		t.Errorf("Expected the auth logger to filter INFO, got %q", auth.String())
+	}
+}
+
+// TestRegistry_UnknownName verifies that an unknown name gets a cached copy of the default logger
+func TestRegistry_UnknownName(t *testing.T) {
+	var buf bytes.Buffer
+	previous := log.Default()
+	defer log.SetDefault(previous)
+	log.SetDefault(log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{}))
+	t.Cleanup(func() { log.Unregister("unregistered") })
+
+	first := log.For("unregistered")
+	if log.For("unregistered") != first {
+		t.Errorf("Expected For to return the same logger for the same name")
+	}
+	first.SetLevel(log.DEBUG)
+	first.Debug("Debug from the copy")
+	log.Default().Debug("Debug from the default")
+
+	if !containsLogMessage(buf.String(), "DEBUG", "Debug from the copy") || bytes.Contains(buf.Bytes(), []byte("from the default")) {
+		t.Errorf("Expected only the copy's level to change, got %q", buf.String())
+	}
+}
+
+// TestRegistry_Concurrent verifies that concurrent lookups of a new name return a single logger
+func TestRegistry_Concurrent(t *testing.T) {
+	t.Cleanup(func() { log.Unregister("concurrent") })
+	loggers := make([]*log.Logger, 8)
+	var wg sync.WaitGroup
+	for i := range loggers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			loggers[i] = log.For("concurrent")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, l := range loggers {
+		if l != loggers[0] {
+			t.Fatalf("Expected every goroutine to get the same logger")
+		}
+	}
+}
+
+// TestRegistry_Unregister verifies that an unregistered name gets a new copy of the default logger
+func TestRegistry_Unregister(t *testing.T) {
+	t.Cleanup(func() { log.Unregister("removed") })
+	registered := log.NewLogger(&bytes.Buffer{}, log.INFO, &log.DefaultFormatter{})
+	log.Register("removed", registered)
+
+	log.Unregister("removed")
+
+	if log.For("removed") == registered {
+		t.Errorf("Expected a new logger after Unregister")
+	}
+}
@@ -0,0 +1,153 @@
+package log_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_Reopen verifies that writes after Reopen go to a new file at the original path
+func TestLogger_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.NewLogger(os.Stdout, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	if err := logger.SetOutputFile(path); err != nil {
+		t.Fatalf("Failed to set output file: %v", err)
+	}
+	defer logger.Shutdown(context.Background())
+
+	logger.Info("Before rotation")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Failed to rename log file: %v", err)
+	}
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Expected Reopen to succeed, got %v", err)
+	}
+	logger.Info("After rotation")
+
+	rotated, current := readLogFile(t, path+".1"), readLogFile(t, path)
+	if !strings.Contains(rotated, "Before rotation") || strings.Contains(rotated, "After rotation") {
+		t.Errorf("Expected only the first message in the rotated file, got %q", rotated)
+	}
+	if !strings.Contains(current, "After rotation") || strings.Contains(current, "Before rotation") {
+		t.Errorf("Expected only the second message in the new file, got %q", current)
+	}
+}
+
+// TestLogger_ReopenConcurrent verifies that no line is lost or split while the file is reopened during writes
+func TestLogger_ReopenConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	logger := log.NewLogger(os.Stdout, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	if err := logger.SetOutputFile(path); err != nil {
+		t.Fatalf("Failed to set output file: %v", err)
+	}
+
+	const goroutines, perGoroutine, rotations = 4, 200, 10
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				logger.Info(fmt.Sprintf("message %d-%d", g, i))
+			}
+		}(g)
+	}
+	for r := 1; r <= rotations; r++ {
+		os.Rename(path, fmt.Sprintf("%s.%d", path, r))
+		if err := logger.Reopen(); err != nil {
+			t.Fatalf("Expected Reopen to succeed, got %v", err)
+		}
+	}
+	wg.Wait()
+	logger.Shutdown(context.Background())
+
+	files, _ := filepath.Glob(filepath.Join(dir, "app.log*"))
+	lines := 0
+	for _, file := range files {
+		for _, line := range strings.Split(strings.TrimSuffix(readLogFile(t, file), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			if !strings.Contains(line, "[INFO] message ") {
+				t.Errorf("Expected a complete line, got %q", line)
+			}
+			lines++
+		}
+	}
+	if lines != goroutines*perGoroutine {
+		t.Errorf("Expected %d lines across the rotated files, got %d", goroutines*perGoroutine, lines)
+	}
+}
+
+// TestLogger_ReopenErrorOutput verifies that Reopen reopens both files when errors are also written to an error output
+func TestLogger_ReopenErrorOutput(t *testing.T) {
+	dir := t.TempDir()
+	appLog, errorLog := filepath.Join(dir, "app.log"), filepath.Join(dir, "errors.log")
+	logger := log.ApplyConfig(log.LoggerConfig{
+		Level:       log.INFO,
+		Output:      appLog,
+		ErrorOutput: errorLog,
+		Format:      "text",
+	})
+
+	logger.Error("Before rotation")
+	for _, path := range []string{appLog, errorLog} {
+		if err := os.Rename(path, path+".1"); err != nil {
+			t.Fatalf("Failed to rename log file: %v", err)
+		}
+	}
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Expected Reopen to succeed, got %v", err)
+	}
+	logger.Error("After rotation")
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to close the log files: %v", err)
+	}
+
+	for _, path := range []string{appLog, errorLog} {
+		rotated, current := readLogFile(t, path+".1"), readLogFile(t, path)
+		if !strings.Contains(rotated, "Before rotation") || strings.Contains(rotated, "After rotation") {
+			t.Errorf("Expected only the first message in %s.1, got %q", filepath.Base(path), rotated)
+		}
+		if !strings.Contains(current, "After rotation") || strings.Contains(current, "Before rotation") {
+			t.Errorf("Expected only the second message in %s, got %q", filepath.Base(path), current)
+		}
+	}
+}
+
+// TestLogger_ReopenNamed verifies that loggers derived before Reopen write to the reopened file
+func TestLogger_ReopenNamed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger := log.NewLogger(os.Stdout, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+	if err := logger.SetOutputFile(path); err != nil {
+		t.Fatalf("Failed to set output file: %v", err)
+	}
+	defer logger.Shutdown(context.Background())
+	child := logger.Named("db")
+	var reported []error
+	child.SetErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Failed to rename log file: %v", err)
+	}
+	if err := logger.Reopen(); err != nil {
+		t.Fatalf("Expected Reopen to succeed, got %v", err)
+	}
+	child.Info("From the child")
+
+	if len(reported) != 0 {
+		t.Errorf("Expected the child to write without errors, got %v", reported)
+	}
+	if current := readLogFile(t, path); !strings.Contains(current, "From the child") {
+		t.Errorf("Expected the child's message in the new file, got %q", current)
+	}
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"io"
+	"sync"
+)
+
+// RingBuffer is an output that keeps the most recent log entries in memory, for
+// example to serve them from a debug endpoint. Each Write is retained as one entry;
+// once the buffer is full the oldest entry is discarded.
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int // Index the next entry is written to
+	full    bool
+
+	subscribers map[chan string]struct{}
+}
+
+// subscriberBuffer is how many entries a subscriber may fall behind before new ones are dropped
+const subscriberBuffer = 256
+
+// NewRingBuffer creates a RingBuffer that retains up to size entries
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{entries: make([][]byte, size)}
+}
+
+// Write stores a copy of p as the newest entry
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	for ch := range r.subscribers {
+		select {
+		case ch <- string(entry):
+		default:
+			// Drop the entry rather than stall logging on a slow subscriber
+		}
+	}
+	return len(p), nil
+}
+
+// Subscribe returns a channel receiving every entry written from now on, for example to
+// stream a live tail to a debug page, and a function that ends the subscription and
+// closes the channel. Up to 256 entries are buffered for a subscriber; entries written
+// while its buffer is full are dropped for that subscriber so that logging never waits.
+func (r *RingBuffer) Subscribe() (<-chan string, func()) {
+	ch := make(chan string, subscriberBuffer)
+	r.mu.Lock()
+	if r.subscribers == nil {
+		r.subscribers = make(map[chan string]struct{})
+	}
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.subscribers, ch)
+			r.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Entries returns the retained entries, oldest first
+func (r *RingBuffer) Entries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var entries []string
+	r.each(func(entry []byte) {
+		entries = append(entries, string(entry))
+	})
+	return entries
+}
+
+// WriteTo writes the retained entries to w, oldest first, and returns the number of bytes written
+func (r *RingBuffer) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	var err error
+	r.each(func(entry []byte) {
+		if err != nil {
+			return
+		}
+		var n int
+		n, err = w.Write(entry)
+		total += int64(n)
+	})
+	return total, err
+}
+
+// each calls fn for every retained entry, oldest first. The caller must hold r.mu.
+func (r *RingBuffer) each(fn func(entry []byte)) {
+	if r.full {
+		for _, entry := range r.entries[r.next:] {
+			fn(entry)
+		}
+	}
+	for _, entry := range r.entries[:r.next] {
+		fn(entry)
+	}
+}
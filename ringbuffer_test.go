@@ -0,0 +1,107 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestRingBuffer_Entries verifies that only the most recent entries are retained, oldest first
+func TestRingBuffer_Entries(t *testing.T) {
+	ring := log.NewRingBuffer(2)
+	logger := log.NewLogger(ring, log.INFO, &MyCustomFormatter{})
+
+	logger.Info("First")
+	logger.Info("Second")
+	logger.Info("Third")
+
+	expected := []string{"**CUSTOM LOG** [INFO] Second\n", "**CUSTOM LOG** [INFO] Third\n"}
+	entries := ring.Entries()
+	if len(entries) != len(expected) || entries[0] != expected[0] || entries[1] != expected[1] {
+		t.Errorf("Expected entries %q, got %q", expected, entries)
+	}
+}
+
+// TestRingBuffer_WriteTo verifies that WriteTo reproduces the retained entries in order with the correct byte count
+func TestRingBuffer_WriteTo(t *testing.T) {
+	ring := log.NewRingBuffer(3)
+	logger := log.NewLogger(ring, log.INFO, &MyCustomFormatter{})
+	for _, message := range []string{"One", "Two", "Three", "Four"} {
+		logger.Info(message)
+	}
+
+	var buf bytes.Buffer
+	n, err := ring.WriteTo(&buf)
+
+	expected := "**CUSTOM LOG** [INFO] Two\n**CUSTOM LOG** [INFO] Three\n**CUSTOM LOG** [INFO] Four\n"
+	if err != nil {
+		t.Fatalf("Expected no error from WriteTo, got %v", err)
+	}
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+	if n != int64(len(expected)) {
+		t.Errorf("Expected %d bytes written, got %d", len(expected), n)
+	}
+}
+
+// TestRingBuffer_WriterTo verifies that RingBuffer can be used wherever an io.WriterTo is expected
+func TestRingBuffer_WriterTo(t *testing.T) {
+	var ring io.WriterTo = log.NewRingBuffer(4)
+	io.WriteString(ring.(io.Writer), "a\n")
+	io.WriteString(ring.(io.Writer), "b\n")
+
+	var buf strings.Builder
+	if _, err := ring.WriteTo(&buf); err != nil {
+		t.Fatalf("Expected no error from WriteTo, got %v", err)
+	}
+	if buf.String() != "a\nb\n" {
+		t.Errorf("Expected %q, got %q", "a\nb\n", buf.String())
+	}
+}
+
+// TestRingBuffer_Subscribe verifies that subscribers receive new entries in order until they unsubscribe
+func TestRingBuffer_Subscribe(t *testing.T) {
+	ring := log.NewRingBuffer(2)
+	logger := log.NewLogger(ring, log.INFO, &MyCustomFormatter{})
+	logger.Info("Before subscribing")
+	entries, unsubscribe := ring.Subscribe()
+
+	logger.Info("One")
+	logger.Info("Two")
+	logger.Info("Three")
+	unsubscribe()
+	logger.Info("After unsubscribing")
+	unsubscribe()
+
+	var received []string
+	for entry := range entries {
+		received = append(received, entry)
+	}
+	expected := []string{"**CUSTOM LOG** [INFO] One\n", "**CUSTOM LOG** [INFO] Two\n", "**CUSTOM LOG** [INFO] Three\n"}
+	if strings.Join(received, "") != strings.Join(expected, "") {
+		t.Errorf("Expected entries %q, got %q", expected, received)
+	}
+}
+
+// TestRingBuffer_SubscribeSlow verifies that a subscriber that does not read never blocks logging
+func TestRingBuffer_SubscribeSlow(t *testing.T) {
+	ring := log.NewRingBuffer(10)
+	logger := log.NewLogger(ring, log.INFO, &MyCustomFormatter{})
+	entries, unsubscribe := ring.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < 1000; i++ {
+		logger.Info("Unread")
+	}
+
+	if len(entries) != cap(entries) {
+		t.Errorf("Expected the subscriber's buffer to be full, got %d of %d entries", len(entries), cap(entries))
+	}
+	if len(ring.Entries()) != 10 {
+		t.Errorf("Expected the ring buffer to keep logging, got %d entries", len(ring.Entries()))
+	}
+}
@@ -0,0 +1,84 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_SetSequenceConcurrent verifies that sequence numbers are unique, gapless and increasing per goroutine
+func TestLogger_SetSequenceConcurrent(t *testing.T) {
+	const goroutines, perGoroutine = 8, 100
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	logger := log.NewLogger(&lockedWriter{mu: &mu, w: &buf}, log.INFO, &log.JSONFormatter{DisableCaller: true})
+	logger.SetSequence(true)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				logger.WithField("goroutine", g).Info("Message")
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	seen := map[uint64]bool{}
+	last := map[int]uint64{}
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var entry struct {
+			Seq       uint64 `json:"seq"`
+			Goroutine int    `json:"goroutine"`
+		}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("Expected valid JSON log messages, got error %v", err)
+		}
+		if seen[entry.Seq] {
+			t.Errorf("Expected unique sequence numbers, got %d twice", entry.Seq)
+		}
+		seen[entry.Seq] = true
+		if entry.Seq <= last[entry.Goroutine] {
+			t.Errorf("Expected increasing sequence numbers in goroutine %d, got %d after %d", entry.Goroutine, entry.Seq, last[entry.Goroutine])
+		}
+		last[entry.Goroutine] = entry.Seq
+	}
+	for seq := uint64(1); seq <= goroutines*perGoroutine; seq++ {
+		if !seen[seq] {
+			t.Errorf("Expected sequence number %d to be present", seq)
+		}
+	}
+}
+
+// TestLogger_SetSequencePerLogger verifies that each logger counts from 1
+func TestLogger_SetSequencePerLogger(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		logger := log.NewLogger(&buf, log.INFO, &log.KeyValueFormatter{DisableCaller: true})
+		logger.SetSequence(true)
+
+		logger.Info("First")
+
+		if !bytes.Contains(buf.Bytes(), []byte("seq=1")) {
+			t.Errorf("Expected logger %d to start at seq=1, got %q", i, buf.String())
+		}
+	}
+}
+
+// lockedWriter serializes writes to w
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// signalShutdownTimeout bounds how long the handler installed by InstallSignalHandler
+// waits for Shutdown, so that an output that cannot be flushed does not keep the signal
+// from taking effect
+var signalShutdownTimeout = 5 * time.Second
+
+// signalNotify and signalStop register and release signal channels; tests replace them
+// to deliver signals without sending them to the process
+var (
+	signalNotify = signal.Notify
+	signalStop   = signal.Stop
+)
+
+// raiseSignal sends sig to the current process
+var raiseSignal = func(sig os.Signal) error {
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return process.Signal(sig)
+}
+
+// InstallSignalHandler shuts the logger down, flushing its output and closing any file it
+// opened, when the process receives one of signals (os.Interrupt and SIGTERM if none are
+// given). Shutdown is given five seconds to complete. The handler then removes itself
+// and, unless disabled with SetSignalReraise, sends the signal to the process again so
+// that its default action, usually exiting, still takes place, even if Shutdown failed. The returned function removes the handler without shutting down.
+func (l *Logger) InstallSignalHandler(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signalNotify(received, signals...)
+
+	go func() {
+		select {
+		case sig := <-received:
+			select {
+			case <-done:
+				return // Stopped while the signal was arriving
+			default:
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), signalShutdownTimeout)
+			l.Shutdown(ctx)
+			cancel()
+			signalStop(received)
+			if l.reraiseSignal.Load() {
+				raiseSignal(sig)
+			}
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signalStop(received)
+			close(done)
+		})
+	}
+}
+
+// SetSignalReraise controls whether the handler installed by InstallSignalHandler sends
+// the signal to the process again after shutting down. It is enabled by default.
+func (l *Logger) SetSignalReraise(reraise bool) {
+	l.reraiseSignal.Store(reraise)
+}
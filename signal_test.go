@@ -0,0 +1,95 @@
+package log_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// closeRecorder records Close calls
+type closeRecorder struct {
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return nil
+}
+
+// fakeSignals replaces signal delivery for a test, returning the channel the handler registered
+func fakeSignals(t *testing.T, raised chan os.Signal) <-chan chan<- os.Signal {
+	registered := make(chan chan<- os.Signal, 1)
+	restore := log.SetSignalFuncs(
+		func(c chan<- os.Signal, sig ...os.Signal) { registered <- c },
+		func(c chan<- os.Signal) {},
+		func(sig os.Signal) error {
+			raised <- sig
+			return nil
+		},
+	)
+	t.Cleanup(restore)
+	return registered
+}
+
+// TestLogger_InstallSignalHandler verifies that a signal flushes and closes the logger and is re-raised
+func TestLogger_InstallSignalHandler(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	registered := fakeSignals(t, raised)
+
+	writer := &flushWriter{}
+	closer := &closeRecorder{}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+	logger.SetCloser(closer)
+	defer logger.InstallSignalHandler(syscall.SIGTERM)()
+
+	(<-registered) <- syscall.SIGTERM
+	if sig := <-raised; sig != syscall.SIGTERM {
+		t.Errorf("Expected SIGTERM to be re-raised, got %v", sig)
+	}
+	if writer.flushed != 1 || closer.closed != 1 {
+		t.Errorf("Expected one Flush and one Close, got %d and %d", writer.flushed, closer.closed)
+	}
+}
+
+// TestLogger_InstallSignalHandlerStop verifies that a stopped handler ignores later signals
+func TestLogger_InstallSignalHandlerStop(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	registered := fakeSignals(t, raised)
+
+	writer := &flushWriter{}
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+	stop := logger.InstallSignalHandler()
+	received := <-registered
+	stop()
+	stop()
+
+	received <- syscall.SIGTERM
+	if writer.flushed != 0 {
+		t.Errorf("Expected no Flush after the handler was stopped, got %d", writer.flushed)
+	}
+}
+
+// TestLogger_InstallSignalHandlerTimeout verifies that the signal is re-raised when Shutdown does not complete
+func TestLogger_InstallSignalHandlerTimeout(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	registered := fakeSignals(t, raised)
+	t.Cleanup(log.SetSignalShutdownTimeout(10 * time.Millisecond))
+
+	writer := &blockingFlusher{release: make(chan struct{})}
+	defer close(writer.release)
+	logger := log.NewLogger(writer, log.INFO, &log.DefaultFormatter{})
+	defer logger.InstallSignalHandler(syscall.SIGTERM)()
+
+	(<-registered) <- syscall.SIGTERM
+	select {
+	case sig := <-raised:
+		if sig != syscall.SIGTERM {
+			t.Errorf("Expected SIGTERM to be re-raised, got %v", sig)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the signal to be re-raised after the shutdown timeout")
+	}
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxStacktraceDepth bounds the number of frames recorded in a stack trace
+const maxStacktraceDepth = 64
+
+// SetStacktraceLevel makes the logger attach a "stacktrace" field to every message at or
+// above level. Pass OFF to disable stack traces, which is the default.
+func (l *Logger) SetStacktraceLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stacktraceLevel = level
+}
+
+// captureStacktrace returns the stack of the code that issued the log call, one
+// "function\n\tfile:line" pair per frame, starting skip frames above its caller and
+// leaving out the logger's own frames at the top of the stack. Runs of identical
+// consecutive frames, as left by recursion, are written once with a " (xN)" suffix.
+func captureStacktrace(skip int) string {
+	var pcs [maxStacktraceDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	var previous runtime.Frame
+	repeats := 0
+	flush := func() {
+		if repeats == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d", previous.Function, previous.File, previous.Line)
+		if repeats > 1 {
+			fmt.Fprintf(&b, " (x%d)", repeats)
+		}
+	}
+
+	inLogger := true
+	for {
+		frame, more := frames.Next()
+		if inLogger && strings.HasPrefix(frame.Function, packagePath+".") {
+			if !more {
+				break
+			}
+			continue
+		}
+		inLogger = false
+		if repeats > 0 && frame.Function == previous.Function && frame.File == previous.File && frame.Line == previous.Line {
+			repeats++
+		} else {
+			flush()
+			previous, repeats = frame, 1
+		}
+		if !more {
+			break
+		}
+	}
+	flush()
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// recurse calls itself depth times and then logs an error
+//
+//go:noinline
+func recurse(logger *log.Logger, depth int) {
+	if depth == 0 {
+		logger.Error("Bottom of the recursion")
+		return
+	}
+	recurse(logger, depth-1)
+}
+
+// TestStacktrace_CollapsesRepeatedFrames verifies that identical consecutive frames are written once with a count
+func TestStacktrace_CollapsesRepeatedFrames(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+	logger.SetStacktraceLevel(log.ERROR)
+
+	recurse(logger, 10)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	stacktrace, _ := entry["stacktrace"].(string)
+	frames := strings.Split(stacktrace, "\n")
+	if len(frames) < 4 {
+		t.Fatalf("Expected at least two frames, got %q", stacktrace)
+	}
+	// The frame that logged, then the ten recursive calls collapsed into one
+	if !strings.HasSuffix(frames[0], ".recurse") || strings.Contains(frames[1], "(x") {
+		t.Errorf("Expected the logging frame first without a count, got %q", frames[:2])
+	}
+	if !strings.HasSuffix(frames[2], ".recurse") || !strings.HasSuffix(frames[3], " (x10)") {
+		t.Errorf("Expected the recursive frames collapsed with (x10), got %q", frames[2:4])
+	}
+	if strings.Count(stacktrace, ".recurse\n") != 2 {
+		t.Errorf("Expected recurse to appear in exactly two frames, got %q", stacktrace)
+	}
+}
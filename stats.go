@@ -0,0 +1,52 @@
+package log
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Reasons a message can be dropped, used as keys of Stats.DroppedByReason
+const (
+	DropQueueFull  = "queue_full"  // An AsyncWriter queue had no room
+	DropWriteError = "write_error" // The output returned any other error
+)
+
+// Stats reports how many messages a logger has handed to its output
+type Stats struct {
+	Written         uint64            // Messages accepted by the output
+	Dropped         uint64            // Messages the output failed to accept
+	DroppedByReason map[string]uint64 // Dropped messages keyed by DropQueueFull or DropWriteError
+}
+
+// logStats holds the counters behind Stats; loggers derived with WithDefaults share their parent's
+type logStats struct {
+	written    atomic.Uint64
+	queueFull  atomic.Uint64
+	writeError atomic.Uint64
+}
+
+// record counts the outcome of writing one message
+func (s *logStats) record(err error) {
+	switch {
+	case err == nil:
+		s.written.Add(1)
+	case errors.Is(err, ErrQueueFull):
+		s.queueFull.Add(1)
+	default:
+		s.writeError.Add(1)
+	}
+}
+
+// Stats returns the number of messages written and dropped so far
+func (l *Logger) Stats() Stats {
+	queueFull := l.stats.queueFull.Load()
+	writeError := l.stats.writeError.Load()
+	return Stats{
+		Written: l.stats.written.Load(),
+		Dropped: queueFull + writeError,
+		DroppedByReason: map[string]uint64{
+			DropQueueFull:  queueFull,
+			DropWriteError: writeError,
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// TestLogger_StatsQueueFull verifies that messages dropped by a full async queue are counted
+func TestLogger_StatsQueueFull(t *testing.T) {
+	output := newGatedWriter()
+	w := log.NewAsyncWriter(output, 1)
+	logger := log.NewLogger(w, log.INFO, &log.DefaultFormatter{DisableCaller: true})
+
+	logger.Info("Written by the background goroutine")
+	<-output.started
+	logger.Info("Queued")
+	logger.Info("Dropped")
+	logger.WithDefaults(log.Fields{"child": true}).Info("Also dropped")
+
+	stats := logger.Stats()
+	if stats.Written != 2 || stats.Dropped != 2 || stats.DroppedByReason[log.DropQueueFull] != 2 {
+		t.Errorf("Expected 2 written and 2 dropped for a full queue, got %+v", stats)
+	}
+
+	close(output.gate)
+	w.Close()
+}
+
+// TestLogger_StatsWriteError verifies that messages the output rejects are counted as dropped
+func TestLogger_StatsWriteError(t *testing.T) {
+	logger := log.NewLogger(failingWriter{}, log.INFO, &log.DefaultFormatter{})
+
+	logger.Info("Lost")
+
+	stats := logger.Stats()
+	if stats.Written != 0 || stats.Dropped != 1 || stats.DroppedByReason[log.DropWriteError] != 1 {
+		t.Errorf("Expected 1 message dropped by a write error, got %+v", stats)
+	}
+}
@@ -0,0 +1,75 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StdLogger has the printing methods of the standard library's *log.Logger and maps
+// them onto a Logger, so that code written against the standard library can switch
+// with few changes. Print methods log at INFO, Fatal methods log at FATAL and exit,
+// and Panic methods log at ERROR and then panic with the message.
+type StdLogger struct {
+	logger *Logger
+}
+
+// StdLogger returns a StdLogger writing through l
+func (l *Logger) StdLogger() *StdLogger {
+	return &StdLogger{logger: l}
+}
+
+// Print logs at INFO, with arguments handled as by fmt.Print
+func (s *StdLogger) Print(v ...interface{}) {
+	s.logger.log(INFO, nil, fmt.Sprint(v...))
+}
+
+// Printf logs at INFO, with arguments handled as by fmt.Printf
+func (s *StdLogger) Printf(format string, v ...interface{}) {
+	s.logger.log(INFO, nil, fmt.Sprintf(format, v...))
+}
+
+// Println logs at INFO, with arguments handled as by fmt.Println
+func (s *StdLogger) Println(v ...interface{}) {
+	s.logger.log(INFO, nil, sprintln(v...))
+}
+
+// Fatal logs at FATAL, which flushes the output and exits the application
+func (s *StdLogger) Fatal(v ...interface{}) {
+	s.logger.log(FATAL, nil, fmt.Sprint(v...))
+}
+
+// Fatalf logs at FATAL, which flushes the output and exits the application
+func (s *StdLogger) Fatalf(format string, v ...interface{}) {
+	s.logger.log(FATAL, nil, fmt.Sprintf(format, v...))
+}
+
+// Fatalln logs at FATAL, which flushes the output and exits the application
+func (s *StdLogger) Fatalln(v ...interface{}) {
+	s.logger.log(FATAL, nil, sprintln(v...))
+}
+
+// Panic logs at ERROR and then panics with the message
+func (s *StdLogger) Panic(v ...interface{}) {
+	s.panic(fmt.Sprint(v...))
+}
+
+// Panicf logs at ERROR and then panics with the message
+func (s *StdLogger) Panicf(format string, v ...interface{}) {
+	s.panic(fmt.Sprintf(format, v...))
+}
+
+// Panicln logs at ERROR and then panics with the message
+func (s *StdLogger) Panicln(v ...interface{}) {
+	s.panic(sprintln(v...))
+}
+
+// panic logs message at ERROR and panics with it, even if ERROR is disabled
+func (s *StdLogger) panic(message string) {
+	s.logger.log(ERROR, nil, message)
+	panic(message)
+}
+
+// sprintln formats v as fmt.Sprintln does, without the trailing newline
+func sprintln(v ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
@@ -0,0 +1,81 @@
+package log_test
+
+import (
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// TestStdLogger_Print verifies that the Print methods log at INFO with standard library formatting
+func TestStdLogger_Print(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	std := logger.StdLogger()
+
+	std.Print("count: ", 3)
+	std.Printf("count: %d", 3)
+	std.Println("count:", 3)
+
+	entries := logger.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Level != log.INFO || entry.Message != "count: 3" {
+			t.Errorf("Expected INFO \"count: 3\", got %v %q", entry.Level, entry.Message)
+		}
+	}
+}
+
+// TestStdLogger_Fatal verifies that the Fatal methods log at FATAL and exit
+func TestStdLogger_Fatal(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	var codes []int
+	logger.SetExitFunc(func(code int) {
+		codes = append(codes, code)
+	})
+	std := logger.StdLogger()
+
+	std.Fatal("stopping")
+	std.Fatalf("stopping %s", "now")
+	std.Fatalln("stopping", "now")
+
+	entries := logger.Entries()
+	if len(entries) != 3 || len(codes) != 3 {
+		t.Fatalf("Expected 3 entries and 3 exits, got %d entries and exit codes %v", len(entries), codes)
+	}
+	for i, expected := range []string{"stopping", "stopping now", "stopping now"} {
+		if entries[i].Level != log.FATAL || entries[i].Message != expected || codes[i] != 1 {
+			t.Errorf("Expected FATAL %q and exit code 1, got %v %q and exit code %d", expected, entries[i].Level, entries[i].Message, codes[i])
+		}
+	}
+}
+
+// TestStdLogger_Panic verifies that the Panic methods log at ERROR and panic with the message
+func TestStdLogger_Panic(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	std := logger.StdLogger()
+
+	tests := []struct {
+		call     func()
+		expected string
+	}{
+		{func() { std.Panic("broken ", 1) }, "broken 1"},
+		{func() { std.Panicf("broken %d", 2) }, "broken 2"},
+		{func() { std.Panicln("broken", 3) }, "broken 3"},
+	}
+	for i, tt := range tests {
+		recovered := func() (r interface{}) {
+			defer func() { r = recover() }()
+			tt.call()
+			return nil
+		}()
+		if recovered != tt.expected {
+			t.Errorf("Expected a panic with %q, got %v", tt.expected, recovered)
+		}
+		entries := logger.Entries()
+		if len(entries) != i+1 || entries[i].Level != log.ERROR || entries[i].Message != tt.expected {
+			t.Errorf("Expected ERROR %q to be logged before panicking, got %+v", tt.expected, entries)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package log
+
+import "fmt"
+
+// missingValue is the value given to a trailing key that has no value
+const missingValue = "(MISSING)"
+
+// Debugw logs a debug message with fields given as alternating keys and values
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.logw(DEBUG, msg, keysAndValues)
+}
+
+// Infow logs an info message with fields given as alternating keys and values:
+//
+//	logger.Infow("Request served", "status", 200, "path", "/index")
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.logw(INFO, msg, keysAndValues)
+}
+
+// Noticew logs a notice message with fields given as alternating keys and values
+func (l *Logger) Noticew(msg string, keysAndValues ...interface{}) {
+	l.logw(NOTICE, msg, keysAndValues)
+}
+
+// Warnw logs a warning message with fields given as alternating keys and values
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.logw(WARN, msg, keysAndValues)
+}
+
+// Errorw logs an error message with fields given as alternating keys and values
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.logw(ERROR, msg, keysAndValues)
+}
+
+// Fatalw logs a fatal message with fields given as alternating keys and values and exits the application
+func (l *Logger) Fatalw(msg string, keysAndValues ...interface{}) {
+	l.logw(FATAL, msg, keysAndValues)
+}
+
+// logw turns keysAndValues into fields and logs msg with them. A trailing key without
+// a value is logged with the value "(MISSING)" and reported in the _field_error field.
+func (l *Logger) logw(level LogLevel, msg string, keysAndValues []interface{}) {
+	if !l.IsLevelEnabled(level) {
+		return
+	}
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	var problems []string
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		if i+1 == len(keysAndValues) {
+			fields = append(fields, Field{Key: key, Value: missingValue})
+			problems = append(problems, fmt.Sprintf("%s: missing value", key))
+			break
+		}
+		fields = append(fields, Field{Key: key, Value: keysAndValues[i+1]})
+	}
+	e := (&Entry{logger: l}).withProblems(fields, problems)
+	l.log(level, e.Fields, msg)
+}
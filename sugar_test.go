@@ -0,0 +1,59 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestLogger_Infow verifies that alternating keys and values are attached as fields
+func TestLogger_Infow(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.Infow("Request served", "status", 200, "path", "/index")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["message"] != "Request served" || entry["status"] != float64(200) || entry["path"] != "/index" {
+		t.Errorf("Expected message, status and path in output, got %v", entry)
+	}
+	if _, ok := entry["_field_error"]; ok {
+		t.Errorf("Expected no _field_error for paired arguments, got %v", entry)
+	}
+}
+
+// TestLogger_InfowOddArguments verifies that a dangling key gets a placeholder value and a warning
+func TestLogger_InfowOddArguments(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.Warnw("Odd", "user", "alice", "attempt")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON log message, got error %v", err)
+	}
+	if entry["user"] != "alice" || entry["attempt"] != "(MISSING)" {
+		t.Errorf("Expected user and a placeholder for attempt, got %v", entry)
+	}
+	if entry["_field_error"] != "attempt: missing value" {
+		t.Errorf("Expected a _field_error warning for attempt, got %v", entry["_field_error"])
+	}
+}
+
+// TestLogger_DebugwFiltered verifies that filtered levels write nothing
+func TestLogger_DebugwFiltered(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{})
+
+	logger.Debugw("Filtered", "key", "value")
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for Debugw at INFO level, got %q", buf.String())
+	}
+}
@@ -0,0 +1,28 @@
+package log
+
+import "sync"
+
+// Timer starts a timer and returns a function that logs msg at INFO with the
+// time elapsed since Timer was called as an "elapsed" field. It is meant to be
+// deferred:
+//
+//	defer logger.Timer("request handled")()
+//
+// The returned function logs at most once, however many times it is called.
+func (l *Logger) Timer(msg string) func() {
+	return l.TimerLevel(INFO, msg)
+}
+
+// TimerLevel is like Timer but logs at the given level
+func (l *Logger) TimerLevel(level LogLevel, msg string) func() {
+	l.mu.RLock()
+	clock := l.clock
+	l.mu.RUnlock()
+	start := clock.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.log(level, []Field{{Key: "elapsed", Value: clock.Now().Sub(start)}}, msg)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// fakeClock is a test clock that only moves when advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// TestLogger_Timer verifies that the timer logs the elapsed time exactly once
+func TestLogger_Timer(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetClock(clock)
+
+	done := logger.Timer("operation finished")
+	clock.Advance(1500 * time.Millisecond)
+	done()
+	done()
+
+	entries := logger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Level != log.INFO || entries[0].Message != "operation finished" {
+		t.Errorf("Expected INFO 'operation finished', got %v", entries[0])
+	}
+	if elapsed := entries[0].Fields["elapsed"]; elapsed != 1500*time.Millisecond {
+		t.Errorf("Expected elapsed 1.5s, got %v", elapsed)
+	}
+}
+
+// TestLogger_TimerLevel verifies that the timer logs at the requested level
+func TestLogger_TimerLevel(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+
+	logger.TimerLevel(log.DEBUG, "debug timing")()
+
+	logger.AssertLogged(t, log.DEBUG, "debug timing")
+}
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TimePrecision controls the sub-second precision of formatted timestamps
+type TimePrecision int
+
+// Timestamp precisions
+const (
+	PrecisionSeconds TimePrecision = iota
+	PrecisionMillis
+	PrecisionMicros
+	PrecisionNanos
+)
+
+// fraction returns the time layout fragment for the precision's fractional seconds
+func (p TimePrecision) fraction() string {
+	switch p {
+	case PrecisionMillis:
+		return ".000"
+	case PrecisionMicros:
+		return ".000000"
+	case PrecisionNanos:
+		return ".000000000"
+	default:
+		return ""
+	}
+}
+
+// textTimeLayout returns the timestamp layout used by the text formatter
+func textTimeLayout(p TimePrecision) string {
+	return "2006-01-02 15:04:05" + p.fraction()
+}
+
+// rfc3339Layout returns an RFC 3339 timestamp layout with the given precision
+func rfc3339Layout(p TimePrecision) string {
+	return "2006-01-02T15:04:05" + p.fraction() + "Z07:00"
+}
+
+// ParseTimePrecision converts a precision name ("s", "ms", "us", "ns" or
+// "seconds", "millis", "micros", "nanos") to the corresponding TimePrecision
+func ParseTimePrecision(precision string) (TimePrecision, error) {
+	switch strings.ToLower(precision) {
+	case "", "s", "seconds":
+		return PrecisionSeconds, nil
+	case "ms", "millis":
+		return PrecisionMillis, nil
+	case "us", "micros":
+		return PrecisionMicros, nil
+	case "ns", "nanos":
+		return PrecisionNanos, nil
+	default:
+		return PrecisionSeconds, fmt.Errorf("unknown time precision %q", precision)
+	}
+}
+
+// String returns the short name of the precision, as accepted by ParseTimePrecision
+func (p TimePrecision) String() string {
+	switch p {
+	case PrecisionMillis:
+		return "ms"
+	case PrecisionMicros:
+		return "us"
+	case PrecisionNanos:
+		return "ns"
+	default:
+		return "s"
+	}
+}
+
+// DurationFormat controls how time.Duration fields are written by JSONFormatter
+type DurationFormat int
+
+// Duration formats
+const (
+	DurationNanos  DurationFormat = iota // An integer number of nanoseconds, as encoding/json writes it
+	DurationString                       // A string such as "1.5s", as the text formatters write it
+)
+
+// ParseDurationFormat converts a duration format name ("ns" or "nanos", "string") to the
+// corresponding DurationFormat
+func ParseDurationFormat(format string) (DurationFormat, error) {
+	switch strings.ToLower(format) {
+	case "", "ns", "nanos":
+		return DurationNanos, nil
+	case "string":
+		return DurationString, nil
+	default:
+		return DurationNanos, fmt.Errorf("unknown duration format %q", format)
+	}
+}
+
+// String returns the name of the duration format, as accepted by ParseDurationFormat
+func (f DurationFormat) String() string {
+	if f == DurationString {
+		return "string"
+	}
+	return "ns"
+}
@@ -0,0 +1,149 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// TestJSONFormatter_NanosecondPrecision verifies that rapid messages get distinct timestamps at nanosecond precision
+func TestJSONFormatter_NanosecondPrecision(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{TimePrecision: log.PrecisionNanos})
+
+	logger.Info("First")
+	logger.Info("Second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %v", lines)
+	}
+	var timestamps []string
+	for _, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Expected valid JSON log message, got error %v", err)
+		}
+		timestamp := entry["timestamp"].(string)
+		if _, err := time.Parse(time.RFC3339Nano, timestamp); err != nil {
+			t.Errorf("Expected an RFC 3339 timestamp, got %v", timestamp)
+		}
+		timestamps = append(timestamps, timestamp)
+	}
+	if timestamps[0] == timestamps[1] {
+		t.Errorf("Expected distinct timestamps, got %v twice", timestamps[0])
+	}
+}
+
+// TestDefaultFormatter_MillisecondPrecision verifies the text timestamp layout at millisecond precision
+func TestDefaultFormatter_MillisecondPrecision(t *testing.T) {
+	var buf bytes.Buffer
+	clock := time.Date(2024, 5, 1, 12, 30, 45, 123456789, time.UTC)
+	logger := log.NewLogger(&buf, log.INFO, &log.DefaultFormatter{DisableCaller: true, TimePrecision: log.PrecisionMillis})
+	logger.SetClock(fixedClock{clock})
+
+	logger.Info("Info message")
+
+	expected := "2024-05-01 12:30:45.123 - [INFO] Info message\n"
+	if buf.String() != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// fixedClock is a log.Clock that always returns the same time
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// clockFunc is a log.Clock that calls the function
+type clockFunc func() time.Time
+
+func (f clockFunc) Now() time.Time {
+	return f()
+}
+
+// TestLogger_SetClock verifies that entries are timestamped by the injected clock
+func TestLogger_SetClock(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{DisableCaller: true, TimePrecision: log.PrecisionMillis})
+	logger.SetClock(fixedClock{time.Date(2024, 5, 1, 12, 30, 45, 123456789, time.UTC)})
+
+	logger.Info("Info message")
+
+	expected := `{"timestamp":"2024-05-01T12:30:45.123Z","level":"INFO","message":"Info message"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, buf.String())
+	}
+}
+
+// TestParseTimePrecision verifies the accepted precision names
+func TestParseTimePrecision(t *testing.T) {
+	precisions := map[string]log.TimePrecision{
+		"":       log.PrecisionSeconds,
+		"ms":     log.PrecisionMillis,
+		"micros": log.PrecisionMicros,
+		"NS":     log.PrecisionNanos,
+	}
+	for name, expected := range precisions {
+		precision, err := log.ParseTimePrecision(name)
+		if err != nil || precision != expected {
+			t.Errorf("Expected %v for %q, got %v (error %v)", expected, name, precision, err)
+		}
+	}
+	if _, err := log.ParseTimePrecision("fortnights"); err == nil {
+		t.Errorf("Expected an error for an unknown precision, got nil")
+	}
+}
+
+// TestJSONFormatter_DurationFormat verifies duration fields in integer and string modes
+func TestJSONFormatter_DurationFormat(t *testing.T) {
+	tests := []struct {
+		format   log.DurationFormat
+		expected interface{}
+	}{
+		{log.DurationNanos, float64(1500000000)},
+		{log.DurationString, "1.5s"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		logger := log.NewLogger(&buf, log.INFO, &log.JSONFormatter{DurationFormat: test.format})
+
+		logger.WithField("elapsed", 1500*time.Millisecond).Info("Done")
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected valid JSON log message, got error %v", err)
+		}
+		if entry["elapsed"] != test.expected {
+			t.Errorf("Expected elapsed %v with format %v, got %v", test.expected, test.format, entry["elapsed"])
+		}
+	}
+}
+
+// TestFormatters_TimeFields verifies that time fields are rendered alike by the text and JSON formatters
+func TestFormatters_TimeFields(t *testing.T) {
+	deadline := time.Date(2024, 5, 1, 12, 30, 45, 123456789, time.UTC)
+	var text, jsonOut bytes.Buffer
+	log.NewLogger(&text, log.INFO, &log.DefaultFormatter{DisableCaller: true}).WithField("deadline", deadline).Info("Scheduled")
+	log.NewLogger(&jsonOut, log.INFO, &log.JSONFormatter{DisableCaller: true}).WithField("deadline", deadline).Info("Scheduled")
+	var millis bytes.Buffer
+	log.NewLogger(&millis, log.INFO, &log.JSONFormatter{FormatTimeFields: true, TimePrecision: log.PrecisionMillis}).WithField("deadline", deadline).Info("Scheduled")
+
+	if !strings.HasSuffix(text.String(), "Scheduled deadline=2024-05-01T12:30:45.123456789Z\n") {
+		t.Errorf("Expected an RFC 3339 deadline in text, got %q", text.String())
+	}
+	if !strings.Contains(jsonOut.String(), `"deadline":"2024-05-01T12:30:45.123456789Z"`) {
+		t.Errorf("Expected the same RFC 3339 deadline in JSON, got %q", jsonOut.String())
+	}
+	if !strings.Contains(millis.String(), `"deadline":"2024-05-01T12:30:45.123Z"`) {
+		t.Errorf("Expected the deadline at the timestamp's precision, got %q", millis.String())
+	}
+}
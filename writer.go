@@ -0,0 +1,134 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// defaultMaxLineBytes bounds the unterminated line a Writer buffers when the logger has
+// no SetMaxMessageBytes limit
+const defaultMaxLineBytes = 64 * 1024
+
+// Writer returns an io.WriteCloser that logs each line written to it at INFO.
+// It lets output from libraries that only accept an io.Writer be routed through the logger.
+func (l *Logger) Writer() io.WriteCloser {
+	return l.WriterLevel(INFO)
+}
+
+// WriterLevel returns an io.WriteCloser that logs each line written to it at the given level.
+// Writes are buffered until a newline is seen, so a single write may produce several
+// entries and a line split across writes produces one. A line longer than the logger's
+// SetMaxMessageBytes limit, or 64 KiB without one, is logged in parts of that size rather
+// than buffered without bound. Close logs any unterminated remainder.
+func (l *Logger) WriterLevel(level LogLevel) io.WriteCloser {
+	return &lineWriter{logger: l, level: level}
+}
+
+// LevelParsingWriter returns an io.WriteCloser that logs each line written to it at the
+// level named at its start, for libraries that prefix their output with a severity such
+// as "[ERROR]" or "WARN:". The prefix is recognised case-insensitively with the names
+// ParseLevel accepts, and removed from the message. Lines without one are logged at INFO.
+// FATAL lines are logged at ERROR, so that a library cannot make the program exit.
+func (l *Logger) LevelParsingWriter() io.WriteCloser {
+	return &lineWriter{logger: l, level: INFO, parseLevel: true}
+}
+
+// lineWriter splits the data written to it into lines and logs each one
+type lineWriter struct {
+	logger     *Logger
+	level      LogLevel
+	parseLevel bool // Take each line's level from its prefix, see LevelParsingWriter
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	limit := w.lineLimit()
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		if i := bytes.IndexByte(data, '\n'); i >= 0 && i <= limit {
+			line := w.buf.Next(i + 1)
+			w.emit(line[:i])
+			continue
+		}
+		if len(data) <= limit {
+			break
+		}
+		// Log the first part of an overlong line, cut so that it does not split a UTF-8 character
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = limit
+		}
+		w.emit(w.buf.Next(cut))
+	}
+	return len(p), nil
+}
+
+// lineLimit returns how many bytes of a line the writer buffers before logging part of it
+func (w *lineWriter) lineLimit() int {
+	w.logger.mu.RLock()
+	defer w.logger.mu.RUnlock()
+	if w.logger.maxMessageBytes > 0 {
+		return w.logger.maxMessageBytes
+	}
+	return defaultMaxLineBytes
+}
+
+// Close logs any data remaining after the last newline
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+// emit logs a single line, dropping a trailing carriage return
+func (w *lineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	level := w.level
+	if w.parseLevel {
+		level, line = splitLevelPrefix(line, level)
+	}
+	w.logger.log(level, nil, string(line))
+}
+
+// splitLevelPrefix returns the level named by a "[LEVEL]" or "LEVEL:" prefix of line and
+// the rest of the line, or fallback and the unchanged line if it has no such prefix
+func splitLevelPrefix(line []byte, fallback LogLevel) (LogLevel, []byte) {
+	var name, rest []byte
+	if bytes.HasPrefix(line, []byte("[")) {
+		end := bytes.IndexByte(line, ']')
+		if end < 0 {
+			return fallback, line
+		}
+		name, rest = line[1:end], line[end+1:]
+	} else {
+		end := bytes.IndexByte(line, ':')
+		if end < 0 {
+			return fallback, line
+		}
+		name, rest = line[:end], line[end+1:]
+	}
+	level, err := ParseLevel(string(name))
+	if err != nil || level == OFF {
+		return fallback, line
+	}
+	if level == FATAL {
+		level = ERROR
+	}
+	return level, bytes.TrimLeft(rest, " \t")
+}
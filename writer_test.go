@@ -0,0 +1,138 @@
+package log_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+	"github.com/pod32g/simple-logger/logtest"
+)
+
+// messages returns the messages recorded by a capture logger
+func messages(logger *logtest.CaptureLogger) []string {
+	var messages []string
+	for _, entry := range logger.Entries() {
+		messages = append(messages, entry.Message)
+	}
+	return messages
+}
+
+// assertMessages fails the test if the recorded messages differ from expected
+func assertMessages(t *testing.T, logger *logtest.CaptureLogger, expected ...string) {
+	t.Helper()
+	got := messages(logger)
+	if len(got) != len(expected) {
+		t.Fatalf("Expected messages %q, got %q", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected messages %q, got %q", expected, got)
+			return
+		}
+	}
+}
+
+// TestLogger_WriterMultiLine verifies that a single write with several lines produces one entry per line
+func TestLogger_WriterMultiLine(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	w := logger.Writer()
+
+	io.WriteString(w, "first\nsecond\r\nthird\n")
+
+	assertMessages(t, logger, "first", "second", "third")
+}
+
+// TestLogger_WriterPartial verifies that a line split across writes is logged once it is complete
+func TestLogger_WriterPartial(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	w := logger.Writer()
+
+	io.WriteString(w, "hel")
+	io.WriteString(w, "lo wor")
+	assertMessages(t, logger)
+
+	io.WriteString(w, "ld\nnext")
+	assertMessages(t, logger, "hello world")
+
+	w.Close()
+	assertMessages(t, logger, "hello world", "next")
+}
+
+// TestLogger_WriterLongLine verifies that a line longer than SetMaxMessageBytes is logged in parts
+// instead of being buffered until its newline
+func TestLogger_WriterLongLine(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetMaxMessageBytes(8)
+	w := logger.Writer()
+
+	io.WriteString(w, "abcdefghijklmnopqrst")
+	assertMessages(t, logger, "abcdefgh", "ijklmnop")
+
+	io.WriteString(w, "\nnext line\n")
+	assertMessages(t, logger, "abcdefgh", "ijklmnop", "qrst", "next lin", "e")
+}
+
+// TestLogger_WriterLongLineUTF8 verifies that a long line is not split inside a UTF-8 character
+func TestLogger_WriterLongLineUTF8(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	logger.SetMaxMessageBytes(4)
+	w := logger.Writer()
+
+	io.WriteString(w, "abcé\n")
+
+	assertMessages(t, logger, "abc", "é")
+}
+
+// TestLogger_WriterDefaultLineLimit verifies that without a message limit a line without a newline is still bounded
+func TestLogger_WriterDefaultLineLimit(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	w := logger.Writer()
+
+	io.WriteString(w, strings.Repeat("x", log.DefaultMaxLineBytes+10))
+
+	got := messages(logger)
+	if len(got) != 1 || len(got[0]) != log.DefaultMaxLineBytes {
+		t.Fatalf("Expected one message of %d bytes, got %d messages", log.DefaultMaxLineBytes, len(got))
+	}
+	w.Close()
+	if got := messages(logger); len(got) != 2 || got[1] != strings.Repeat("x", 10) {
+		t.Errorf("Expected Close to log the 10 remaining bytes, got %d messages", len(got))
+	}
+}
+
+// TestLogger_WriterLevel verifies that lines are logged at the writer's level
+func TestLogger_WriterLevel(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	w := logger.WriterLevel(log.ERROR)
+
+	io.WriteString(w, "driver failure\n")
+
+	logger.AssertLogged(t, log.ERROR, "driver failure")
+}
+
+// TestLogger_LevelParsingWriter verifies that each line is logged at the level named by its prefix
+func TestLogger_LevelParsingWriter(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.DEBUG)
+	w := logger.LevelParsingWriter()
+
+	io.WriteString(w, "ERROR: connection refused\nWARN: slow query\n[debug] pool stats\nplain line\nFATAL: cannot recover\n")
+
+	assertMessages(t, logger, "connection refused", "slow query", "pool stats", "plain line", "cannot recover")
+	expected := []log.LogLevel{log.ERROR, log.WARN, log.DEBUG, log.INFO, log.ERROR}
+	for i, entry := range logger.Entries() {
+		if entry.Level != expected[i] {
+			t.Errorf("Expected %q at %v, got %v", entry.Message, expected[i], entry.Level)
+		}
+	}
+}
+
+// TestLogger_LevelParsingWriterUnknownPrefix verifies that a prefix that is not a level is kept in the message
+func TestLogger_LevelParsingWriterUnknownPrefix(t *testing.T) {
+	logger := logtest.NewCaptureLogger(log.INFO)
+	w := logger.LevelParsingWriter()
+
+	io.WriteString(w, "note: vacuum finished\n")
+
+	logger.AssertLogged(t, log.INFO, "note: vacuum finished")
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// XMLFormatter formats log messages as XML <log> elements
+type XMLFormatter struct {
+	DisableCaller bool          // Skip the caller lookup and omit the file and line elements
+	CallerFormat  CallerFormat  // How the caller's file is rendered, base name by default
+	TimePrecision TimePrecision // Sub-second precision of the timestamp, seconds by default
+}
+
+// xmlEntry is the XML representation of a single log message
+type xmlEntry struct {
+	XMLName   xml.Name   `xml:"log"`
+	Timestamp string     `xml:"timestamp"`
+	Level     string     `xml:"level"`
+	File      string     `xml:"file,omitempty"`
+	Line      int        `xml:"line,omitempty"`
+	Message   string     `xml:"message"`
+	Fields    []xmlField `xml:"field"`
+}
+
+// xmlField is a structured field, written as <field key="name">value</field>
+// because field names aren't necessarily valid element names
+type xmlField struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (f *XMLFormatter) Format(level LogLevel, message string) string {
+	return f.FormatEntry(newEntry(level, message, f.reportsCaller()))
+}
+
+// FormatEntry formats an entry as a <log> element
+func (f *XMLFormatter) FormatEntry(e Entry) string {
+	entry := xmlEntry{
+		Timestamp: e.Time.Format(rfc3339Layout(f.TimePrecision)),
+		Level:     e.Level.String(),
+		Message:   e.Message,
+	}
+	if !f.DisableCaller && !e.skipCaller {
+		entry.File, entry.Line = callerFile(e, f.CallerFormat), e.Line
+	}
+	for _, field := range e.Fields {
+		entry.Fields = append(entry.Fields, xmlField{Key: field.Key, Value: xmlValue(field.Value)})
+	}
+	xmlLog, err := xml.Marshal(entry)
+	if err != nil {
+		return "<log><error>failed to format log message</error></log>\n"
+	}
+	return string(xmlLog) + "\n"
+}
+
+// xmlValue renders a field value as text, writing times in RFC 3339 like the other formatters
+func xmlValue(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+func (f *XMLFormatter) reportsCaller() bool {
+	return !f.DisableCaller
+}
@@ -0,0 +1,96 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	log "github.com/pod32g/simple-logger"
+)
+
+// xmlLog mirrors the element written by XMLFormatter
+type xmlLog struct {
+	XMLName   xml.Name `xml:"log"`
+	Timestamp string   `xml:"timestamp"`
+	Level     string   `xml:"level"`
+	File      string   `xml:"file"`
+	Line      int      `xml:"line"`
+	Message   string   `xml:"message"`
+}
+
+// TestXMLFormatter verifies that the XML formatter emits a <log> element with the expected fields
+func TestXMLFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.XMLFormatter{})
+
+	logger.Warn("XML Warn message")
+
+	var entry xmlLog
+	if err := xml.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid XML log message, got error %v: %v", err, buf.String())
+	}
+	if entry.Level != "WARN" {
+		t.Errorf("Expected level 'WARN', got '%v'", entry.Level)
+	}
+	if entry.Message != "XML Warn message" {
+		t.Errorf("Expected message 'XML Warn message', got '%v'", entry.Message)
+	}
+	if entry.File != "xml_test.go" {
+		t.Errorf("Expected file 'xml_test.go', got '%v'", entry.File)
+	}
+	if entry.Timestamp == "" {
+		t.Errorf("Expected a timestamp, got none")
+	}
+}
+
+// TestXMLFormatter_Escaping verifies that special characters in the message are escaped
+func TestXMLFormatter_Escaping(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.XMLFormatter{DisableCaller: true})
+
+	message := `a < b && c > "d"`
+	logger.Info(message)
+
+	if bytes.Contains(buf.Bytes(), []byte(message)) {
+		t.Errorf("Expected message to be escaped, got %v", buf.String())
+	}
+	var entry xmlLog
+	if err := xml.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid XML log message, got error %v: %v", err, buf.String())
+	}
+	if entry.Message != message {
+		t.Errorf("Expected message '%v', got '%v'", message, entry.Message)
+	}
+	if entry.File != "" {
+		t.Errorf("Expected no file element when caller is disabled, got '%v'", entry.File)
+	}
+}
+
+// TestXMLFormatter_Fields verifies that structured fields are written as escaped <field> elements
+func TestXMLFormatter_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(&buf, log.INFO, &log.XMLFormatter{DisableCaller: true})
+
+	value := `<&">`
+	logger.WithFields(log.Fields{"user": "alice", "raw": value}).Info("XML fields message")
+
+	if bytes.Contains(buf.Bytes(), []byte(value)) {
+		t.Errorf("Expected field value to be escaped, got %v", buf.String())
+	}
+	var entry struct {
+		Fields []struct {
+			Key   string `xml:"key,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"field"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid XML log message, got error %v: %v", err, buf.String())
+	}
+	fields := map[string]string{}
+	for _, field := range entry.Fields {
+		fields[field.Key] = field.Value
+	}
+	if len(fields) != 2 || fields["user"] != "alice" || fields["raw"] != value {
+		t.Errorf("Expected fields user=alice and raw=%v, got %v", value, fields)
+	}
+}